@@ -0,0 +1,43 @@
+// (C) Copyright 2023 Hewlett Packard Enterprise Development LP
+
+// hpegl-caas-credential is a kubectl exec credential plugin for CaaS clusters.  It is configured with the
+// same HPEGL_* env-vars as the hpegl provider, and is referenced by the exec_kubeconfig of a cluster.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/HewlettPackard/hpegl-containers-terraform-resources/pkg/constants"
+	"github.com/HewlettPackard/hpegl-containers-terraform-resources/pkg/credential"
+)
+
+func main() {
+	var clusterID, apiURL string
+
+	defaultAPIURL := os.Getenv(constants.APIURLEnvVar)
+	if defaultAPIURL == "" {
+		defaultAPIURL = constants.DefaultAPIURL
+	}
+
+	flag.StringVar(&clusterID, "cluster-id", "", "the ID of the CaaS cluster")
+	flag.StringVar(&apiURL, "api-url", defaultAPIURL, "the URL of the CaaS API, defaults to "+constants.APIURLEnvVar)
+	flag.Parse()
+
+	if clusterID == "" {
+		fmt.Fprintln(os.Stderr, "--cluster-id must be set")
+		os.Exit(1)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if err := credential.Run(ctx, clusterID, apiURL, os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %s\n", credential.Command, err)
+		stop()
+		os.Exit(1)
+	}
+}
@@ -28,23 +28,25 @@ data "hpegl_caas_cluster" "test" {
   space_id = var.HPEGL_SPACE
 }
 
-resource "local_file" "foo" {
-  content  = base64decode(data.hpegl_caas_cluster.test.kubeconfig)
-  filename = "./kubeconfig"
+resource "hpegl_caas_kubeconfig_file" "kubeconfig" {
+  path = "./kubeconfig"
+  cluster {
+    cluster_id = data.hpegl_caas_cluster.test.id
+  }
 }
 
 provider "kubernetes" {
-  host     = yamldecode(base64decode(data.hpegl_caas_cluster.test.kubeconfig)).clusters[0].cluster.server
-  token    = yamldecode(base64decode(data.hpegl_caas_cluster.test.kubeconfig)).users[0].user.token
-  insecure = true
+  host                   = data.hpegl_caas_cluster.test.kube_host
+  token                  = data.hpegl_caas_cluster.test.kube_token
+  cluster_ca_certificate = data.hpegl_caas_cluster.test.cluster_ca_certificate
 }
 
 
 provider "kubectl" {
-  host             = yamldecode(base64decode(data.hpegl_caas_cluster.test.kubeconfig)).clusters[0].cluster.server
-  token            = yamldecode(base64decode(data.hpegl_caas_cluster.test.kubeconfig)).users[0].user.token
-  insecure         = true
-  load_config_file = false
+  host                   = data.hpegl_caas_cluster.test.kube_host
+  token                  = data.hpegl_caas_cluster.test.kube_token
+  cluster_ca_certificate = data.hpegl_caas_cluster.test.cluster_ca_certificate
+  load_config_file       = false
 }
 
 resource "kubernetes_namespace" "onlineboutique" {
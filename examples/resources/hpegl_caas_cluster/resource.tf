@@ -39,6 +39,16 @@ resource hpegl_caas_cluster test {
   site_id = data.hpegl_caas_site.blr.id
   space_id     = var.HPEGL_SPACE
   kubernetes_version = ""
+  ignore_autoscaler_changes = true
+  capacity_check = "warn"
+  wait_for_health = true
+  acceptable_health = ["ok"]
+  wait_for_api_server = true
+  # Store the kubeconfig encrypted to a PGP key instead of in cleartext
+  # pgp_key = "keybase:username"
+  control_plane {
+      count = 3
+    }
   worker_nodes {
       name = "worker"
       machine_blueprint_id = data.hpegl_caas_machine_blueprint.mbworker.id
@@ -0,0 +1,46 @@
+# Copyright 2023 Hewlett Packard Enterprise Development LP
+
+terraform {
+  required_providers {
+    hpegl = {
+      source = "HPE/hpegl"
+      version = ">= 0.1.0"
+    }
+  }
+}
+
+provider hpegl {
+  caas {
+  }
+}
+
+variable "HPEGL_SPACE" {
+  type = string
+}
+
+data "hpegl_caas_cluster" "test" {
+  name     = "tf-test"
+  space_id = var.HPEGL_SPACE
+}
+
+data "hpegl_caas_cluster_machines" "workers" {
+  cluster_id = data.hpegl_caas_cluster.test.id
+  space_id   = var.HPEGL_SPACE
+  roles      = ["worker"]
+}
+
+data "hpegl_caas_cluster_machines" "unhealthy" {
+  cluster_id = data.hpegl_caas_cluster.test.id
+  space_id   = var.HPEGL_SPACE
+  health     = ["warning", "error", "unknown"]
+}
+
+output "worker_hostnames" {
+  description = "The hostnames of the worker machines"
+  value       = data.hpegl_caas_cluster_machines.workers.machines[*].hostname
+}
+
+output "unhealthy_machines" {
+  description = "The machines that are not healthy"
+  value       = data.hpegl_caas_cluster_machines.unhealthy.machines
+}
@@ -0,0 +1,42 @@
+# Copyright 2023 Hewlett Packard Enterprise Development LP
+
+terraform {
+  required_providers {
+    hpegl = {
+      source = "HPE/hpegl"
+      version = ">= 0.1.0"
+    }
+  }
+}
+
+provider hpegl {
+  caas {
+  }
+}
+
+variable "HPEGL_SPACE" {
+  type = string
+}
+
+data "hpegl_caas_site" "blr" {
+  name = "BLR"
+  space_id = var.HPEGL_SPACE
+}
+
+data "hpegl_caas_cluster_provider_selection" "best" {
+  site_id = data.hpegl_caas_site.blr.id
+  kubernetes_version = "v1.24.8-hpe1"
+  acceptable_health = ["ok"]
+  access_protocol = "iscsi"
+  encryption = "true"
+  min_iops = 5000
+}
+
+output "cluster_provider" {
+  description = "The best matching cluster provider and storage class"
+  value = {
+    name          = data.hpegl_caas_cluster_provider_selection.best.name
+    storage_class = data.hpegl_caas_cluster_provider_selection.best.storage_class
+    ranking       = data.hpegl_caas_cluster_provider_selection.best.ranking
+  }
+}
@@ -0,0 +1,48 @@
+# Copyright 2023 Hewlett Packard Enterprise Development LP
+
+terraform {
+  required_providers {
+    hpegl = {
+      source = "HPE/hpegl"
+      version = ">= 0.1.0"
+    }
+  }
+}
+
+provider hpegl {
+  caas {
+  }
+}
+
+variable "HPEGL_SPACE" {
+  type = string
+}
+
+data "hpegl_caas_site" "blr" {
+  name = "BLR"
+  space_id = var.HPEGL_SPACE
+}
+
+data "hpegl_caas_cluster_blueprint" "bp" {
+  name = "demo"
+  site_id = data.hpegl_caas_site.blr.id
+}
+
+resource hpegl_caas_cluster test {
+  name         = "tf-test"
+  blueprint_id = data.hpegl_caas_cluster_blueprint.bp.id
+  site_id = data.hpegl_caas_site.blr.id
+  space_id     = var.HPEGL_SPACE
+  wait_for_completion = false
+}
+
+resource hpegl_caas_cluster_wait test {
+  cluster_id = hpegl_caas_cluster.test.id
+  space_id = var.HPEGL_SPACE
+  target_state = "ready"
+  wait_for_health = true
+
+  timeouts {
+    create = "90m"
+  }
+}
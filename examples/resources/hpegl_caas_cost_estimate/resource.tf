@@ -0,0 +1,60 @@
+# Copyright 2023 Hewlett Packard Enterprise Development LP
+
+terraform {
+  required_providers {
+    hpegl = {
+      source = "HPE/hpegl"
+      version = ">= 0.1.0"
+    }
+  }
+}
+
+provider hpegl {
+  caas {
+  }
+}
+
+variable "HPEGL_SPACE" {
+  type = string
+}
+
+data "hpegl_caas_site" "blr" {
+  name = "BLR"
+  space_id = var.HPEGL_SPACE
+}
+
+data "hpegl_caas_cluster_blueprint" "bp" {
+  name = "demo"
+  site_id = data.hpegl_caas_site.blr.id
+}
+
+data "hpegl_caas_machine_blueprint" "mbworker" {
+  name = "standard-worker"
+  site_id = data.hpegl_caas_site.blr.id
+}
+
+data "hpegl_caas_cost_estimate" "blueprint" {
+  site_id = data.hpegl_caas_site.blr.id
+  cluster_blueprint_id = data.hpegl_caas_cluster_blueprint.bp.id
+  storage_class = "gl-sbc-hpe"
+}
+
+data "hpegl_caas_cost_estimate" "workers" {
+  site_id = data.hpegl_caas_site.blr.id
+  storage_class = "gl-sbc-hpe"
+  machine_sets {
+    name = "worker"
+    machine_blueprint_id = data.hpegl_caas_machine_blueprint.mbworker.id
+    count = 3
+  }
+}
+
+output "blueprint_estimate" {
+  description = "The estimated resources and monthly storage cost of the blueprint"
+  value       = data.hpegl_caas_cost_estimate.blueprint.total
+}
+
+output "workers_estimate" {
+  description = "The estimated resources and monthly storage cost of each worker pool"
+  value       = data.hpegl_caas_cost_estimate.workers.machine_set_estimates
+}
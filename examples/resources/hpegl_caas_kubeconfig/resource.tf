@@ -0,0 +1,39 @@
+# Copyright 2023 Hewlett Packard Enterprise Development LP
+
+terraform {
+  required_providers {
+    hpegl = {
+      source = "HPE/hpegl"
+      version = ">= 0.1.0"
+    }
+  }
+}
+
+provider hpegl {
+  caas {
+  }
+}
+
+variable "HPEGL_SPACE" {
+  type = string
+}
+
+data "hpegl_caas_cluster" "test" {
+  name     = "test"
+  space_id = var.HPEGL_SPACE
+}
+
+data "hpegl_caas_kubeconfig" "test" {
+  cluster_id             = data.hpegl_caas_cluster.test.id
+  min_remaining_validity = "30m"
+}
+
+provider "kubernetes" {
+  host                   = data.hpegl_caas_kubeconfig.test.kube_host
+  token                  = data.hpegl_caas_kubeconfig.test.kube_token
+  cluster_ca_certificate = data.hpegl_caas_kubeconfig.test.cluster_ca_certificate
+}
+
+output "token_expiry" {
+  value = data.hpegl_caas_kubeconfig.test.token_expiry
+}
@@ -0,0 +1,44 @@
+# Copyright 2023 Hewlett Packard Enterprise Development LP
+
+terraform {
+  required_providers {
+    hpegl = {
+      source = "HPE/hpegl"
+      version = ">= 0.1.0"
+    }
+  }
+}
+
+provider hpegl {
+  caas {
+  }
+}
+
+variable "HPEGL_SPACE" {
+  type = string
+}
+
+data "hpegl_caas_cluster" "dev" {
+  name     = "dev"
+  space_id = var.HPEGL_SPACE
+}
+
+data "hpegl_caas_cluster" "test" {
+  name     = "test"
+  space_id = var.HPEGL_SPACE
+}
+
+resource "hpegl_caas_kubeconfig_file" "workstation" {
+  path = "~/.kube/config"
+  cluster {
+    cluster_id   = data.hpegl_caas_cluster.dev.id
+    context_name = "caas-dev"
+  }
+  cluster {
+    cluster_id   = data.hpegl_caas_cluster.test.id
+    context_name = "caas-test"
+    namespace    = "default"
+  }
+  current_context = "caas-dev"
+  exec_credential = true
+}
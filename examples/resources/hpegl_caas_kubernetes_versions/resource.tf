@@ -0,0 +1,41 @@
+# Copyright 2023 Hewlett Packard Enterprise Development LP
+
+terraform {
+  required_providers {
+    hpegl = {
+      source = "HPE/hpegl"
+      version = ">= 0.1.0"
+    }
+  }
+}
+
+provider hpegl {
+  caas {
+  }
+}
+
+variable "HPEGL_SPACE" {
+  type = string
+}
+
+data "hpegl_caas_site" "blr" {
+  name = "BLR"
+  space_id = var.HPEGL_SPACE
+}
+
+data "hpegl_caas_kubernetes_versions" "v124" {
+  site_id = data.hpegl_caas_site.blr.id
+  cluster_provider = "ecp"
+  minor = "1.24"
+  upgrade_from = "1.23.13-hpe2"
+}
+
+output "latest_1_24" {
+  description = "The newest 1.24 patch version"
+  value       = data.hpegl_caas_kubernetes_versions.v124.latest
+}
+
+output "upgrade_path" {
+  description = "The versions to upgrade through to get to the newest 1.24 version"
+  value       = data.hpegl_caas_kubernetes_versions.v124.upgrade_path
+}
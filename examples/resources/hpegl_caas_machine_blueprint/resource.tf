@@ -28,7 +28,6 @@ resource hpegl_caas_machine_blueprint test {
  site_id = data.hpegl_caas_site.blr.id
  machine_roles = ["controlplane"]
  machine_provider = "vmaas"
- worker_type = ""
  compute_type = ""
  size = ""
  storage_type = ""
@@ -0,0 +1,42 @@
+# Copyright 2023 Hewlett Packard Enterprise Development LP
+
+terraform {
+  required_providers {
+    hpegl = {
+      source = "HPE/hpegl"
+      version = ">= 0.1.0"
+    }
+  }
+}
+
+provider hpegl {
+  caas {
+  }
+}
+
+variable "HPEGL_SPACE" {
+  type = string
+}
+
+data "hpegl_caas_site" "blr" {
+  name = "BLR"
+  space_id = var.HPEGL_SPACE
+}
+
+data "hpegl_caas_machine_size_catalog" "vmaas" {
+  site_id = data.hpegl_caas_site.blr.id
+  machine_provider = "vmaas"
+  min_cpu = 4
+  min_memory = 16
+}
+
+resource hpegl_caas_machine_blueprint test {
+ name = "tf-worker"
+ site_id = data.hpegl_caas_site.blr.id
+ machine_roles = ["worker"]
+ machine_provider = "vmaas"
+ worker_type = data.hpegl_caas_machine_size_catalog.vmaas.smallest_size[0].worker_type
+ compute_type = data.hpegl_caas_machine_size_catalog.vmaas.smallest_size[0].compute_type
+ size = data.hpegl_caas_machine_size_catalog.vmaas.smallest_size[0].name
+ storage_type = data.hpegl_caas_machine_size_catalog.vmaas.storage_types[0]
+}
@@ -4,12 +4,14 @@ package acceptancetest
 
 import (
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 
 	libUtils "github.com/hewlettpackard/hpegl-provider-lib/pkg/utils"
 
+	"github.com/HewlettPackard/hpegl-containers-terraform-resources/pkg/constants"
 	testutils "github.com/HewlettPackard/hpegl-containers-terraform-resources/pkg/test-utils"
 )
 
@@ -44,6 +46,12 @@ func TestProvider(t *testing.T) {
 func TestMain(m *testing.M) {
 	// TF_ACC_CONFIG_PATH set in make acceptance
 	libUtils.ReadAccConfig(os.Getenv("TF_ACC_CONFIG_PATH"))
-	m.Run()
-	os.Exit(0)
+	// The acceptance tests select their environment with a profile from the repo profiles file
+	if os.Getenv(constants.ProfilesFileEnvVar) == "" {
+		profilesFile, err := filepath.Abs(filepath.Join("..", "..", "profiles.yaml"))
+		if err == nil {
+			os.Setenv(constants.ProfilesFileEnvVar, profilesFile)
+		}
+	}
+	os.Exit(m.Run())
 }
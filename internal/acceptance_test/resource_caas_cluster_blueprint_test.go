@@ -29,9 +29,9 @@ const (
 	workerMinSize = "1"
 	workerMaxSize = "1"
 	//kubernetesVersion   = "1.24.10-hpe1"
-	apiURLCbp   = "https://mcaas.intg.hpedevops.net/mcaas"
+	profileCbp  = "integ"
 	siteNameCBp = "FTC"
-	//apiURLCbp           = "https://mcaas.us1.greenlake-hpe.com/mcaas"
+	//profileCbp = "us1"
 )
 
 // nolint: gosec
@@ -41,7 +41,7 @@ func testCaasClusterBlueprint() string {
 	return fmt.Sprintf(`
 	provider hpegl {
 		caas {
-			api_url = "%s"
+			profile = "%s"
 		}
 	}
 
@@ -77,7 +77,7 @@ func testCaasClusterBlueprint() string {
 			min_size = "%s"
 			max_size = "%s"
     	}
-	}`, apiURLCbp, siteNameCBp, name, r.Int63n(99999999), defaultStorageClass, clusterProvider, cpCount, workerName, workerMinSize, workerMaxSize)
+	}`, profileCbp, siteNameCBp, name, r.Int63n(99999999), defaultStorageClass, clusterProvider, cpCount, workerName, workerMinSize, workerMaxSize)
 }
 
 func TestCaasClusterBlueprintCreate(t *testing.T) {
@@ -7,6 +7,8 @@ import (
 	"crypto/rand"
 	"encoding/hex"
 	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -17,18 +19,19 @@ import (
 
 	"github.com/HewlettPackard/hpegl-containers-terraform-resources/pkg/auth"
 	"github.com/HewlettPackard/hpegl-containers-terraform-resources/pkg/client"
+	"github.com/HewlettPackard/hpegl-containers-terraform-resources/pkg/recorder"
 	"github.com/HewlettPackard/hpegl-containers-terraform-resources/pkg/utils"
 )
 
 const (
 	clusterPrefix           = "test"
-	apiURL                  = "https://mcaas.intg.hpedevops.net/mcaas"
+	testProfile             = "integ"
 	siteName                = "FTC"
 	testWorkerNode          = "worker2"
 	kubernetesVersionUpdate = "1.23.13-hpe2"
 	scaleWorkerMinSize      = "2"
 	scaleWorkerMaxSize      = "4"
-	//apiURL         = "https://mcaas.us1.greenlake-hpe.com/mcaas"
+	//testProfile = "us1"
 )
 
 // nolint: gosec
@@ -36,7 +39,7 @@ func testCaasCluster(clusterName string) string {
 	return fmt.Sprintf(`
 	provider hpegl {
 		caas {
-			api_url = "%s"
+			profile = "%s"
 		}
 	}
 	variable "HPEGL_SPACE" {
@@ -58,7 +61,7 @@ func testCaasCluster(clusterName string) string {
 		timeouts {
 			create = "2h"
 		}
-	}`, apiURL, siteName, clusterName)
+	}`, testProfile, siteName, clusterName)
 }
 
 // nolint: gosec
@@ -66,7 +69,7 @@ func testCaasClusterUpdate(clusterName string) string {
 	return fmt.Sprintf(`
 	provider hpegl {
 		caas {
-			api_url = "%s" 
+			profile = "%s"
 		}
 	}
 	variable "HPEGL_SPACE" {
@@ -100,14 +103,14 @@ func testCaasClusterUpdate(clusterName string) string {
 			create = "2h"
             update = "2h"
 		}
-	}`, apiURL, siteName, clusterName, testWorkerNode, scaleWorkerMinSize, scaleWorkerMaxSize)
+	}`, testProfile, siteName, clusterName, testWorkerNode, scaleWorkerMinSize, scaleWorkerMaxSize)
 }
 
 func testCaasClusterk8sVersionUpdate(clusterName string) string {
 	return fmt.Sprintf(`
 	provider hpegl {
 		caas {
-			api_url = "%s"
+			profile = "%s"
 		}
 	}
 	variable "HPEGL_SPACE" {
@@ -131,7 +134,7 @@ func testCaasClusterk8sVersionUpdate(clusterName string) string {
 			create = "2h"
             update = "2h"
 		}
-	}`, apiURL, siteName, clusterName, kubernetesVersionUpdate)
+	}`, testProfile, siteName, clusterName, kubernetesVersionUpdate)
 }
 
 func TestCaasCreate(t *testing.T) {
@@ -139,7 +142,8 @@ func TestCaasCreate(t *testing.T) {
 		t.Skip("Skipping CaaS cluster creation in short mode.")
 	}
 
-	clusterName := fmt.Sprintf("%s-%s", clusterPrefix, randomHex(5))
+	testAccFixture(t, "TestCaasCreate")
+	clusterName := testClusterName()
 
 	resource.Test(t, resource.TestCase{
 		PreCheck:                  func() { testAccPreCheck(t) },
@@ -317,6 +321,40 @@ func testCaasClusterDestroy(name string) resource.TestCheckFunc {
 	}
 }
 
+// testAccFixture points the recorder at the fixture for the test if HPEGL_CAAS_RECORDER_MODE is set, the
+// recorder is reset when the test finishes so that the next run replays the fixture from the start
+func testAccFixture(t *testing.T, name string) {
+	t.Helper()
+	if recorder.Mode() == "" {
+		return
+	}
+
+	path, err := filepath.Abs(filepath.Join("testdata", "fixtures", name+".json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if recorder.Replaying() {
+		if _, err = os.Stat(path); err != nil {
+			t.Fatalf("no fixture to replay for %s, record one against integration with make acceptance-record "+
+				"and commit %s: %s", name, path, err)
+		}
+	}
+
+	t.Setenv(recorder.FixtureEnvVar, path)
+	t.Cleanup(func() { recorder.Reset(path) })
+}
+
+// testClusterName returns a random cluster name, or a fixed one if API exchanges are being
+// recorded or replayed since the name is part of the recorded requests and responses
+func testClusterName() string {
+	if recorder.Mode() != "" {
+		return clusterPrefix + "-recorded"
+	}
+
+	return fmt.Sprintf("%s-%s", clusterPrefix, randomHex(5))
+}
+
 func randomHex(n int) string {
 	bytes := make([]byte, n)
 	rand.Read(bytes)
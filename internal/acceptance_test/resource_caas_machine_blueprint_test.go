@@ -23,10 +23,10 @@ const (
 	computeType     = "General Purpose"
 	size            = "G1-CN-xLarge"
 	storageType     = "General Purpose"
-	apiURLMBp       = "https://mcaas.intg.hpedevops.net/mcaas"
+	profileMBp      = "integ"
 	siteNameMbp     = "FTC"
 	workerType      = "Virtual"
-	//apiURLMBp       = "https://mcaas.us1.greenlake-hpe.com/mcaas"
+	//profileMBp = "us1"
 )
 
 var machineRoles = []string{"worker"}
@@ -37,7 +37,7 @@ func testCaasMachineBlueprint() string {
 	return fmt.Sprintf(`
 	provider hpegl {
 		caas {
-			api_url = "%s"
+			profile = "%s"
 		}
 	}
 	variable "HPEGL_SPACE" {
@@ -56,7 +56,7 @@ func testCaasMachineBlueprint() string {
 		size = "%s"
 		storage_type = "%s"
         worker_type = "%s"
-	}`, apiURLMBp, siteNameMbp, nameMbp, r.Int63n(99999999), machineRoles, machineProvider, computeType, size, storageType, workerType)
+	}`, profileMBp, siteNameMbp, nameMbp, r.Int63n(99999999), machineRoles, machineProvider, computeType, size, storageType, workerType)
 }
 
 func TestCaasMachineBlueprintCreate(t *testing.T) {
@@ -0,0 +1,297 @@
+// (C) Copyright 2023 Hewlett Packard Enterprise Development LP
+
+package acceptancetest
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/HewlettPackard/hpegl-containers-terraform-resources/pkg/resources"
+)
+
+// updateSchemas rewrites the golden schema files, run with:
+//
+//	go test ./internal/acceptance_test -run TestSchemaCompatibility -update
+//
+// The golden files are only rewritten if the schema changes are backward-compatible, or if the
+// SchemaVersion has been bumped with a matching StateUpgrader
+var updateSchemas = flag.Bool("update", false, "update the golden schema files")
+
+const schemaGoldenDir = "testdata/schemas"
+
+// schemaSnapshot is the serialised form of a resource or data-source schema
+type schemaSnapshot struct {
+	SchemaVersion int                          `json:"schema_version"`
+	Attributes    map[string]attributeSnapshot `json:"attributes"`
+}
+
+// attributeSnapshot holds the properties of an attribute that matter for compatibility
+type attributeSnapshot struct {
+	Type     string `json:"type"`
+	ElemType string `json:"elem_type,omitempty"`
+	Required bool   `json:"required,omitempty"`
+	Optional bool   `json:"optional,omitempty"`
+	Computed bool   `json:"computed,omitempty"`
+	ForceNew bool   `json:"force_new,omitempty"`
+}
+
+func TestSchemaCompatibility(t *testing.T) {
+	reg := resources.Registration{}
+
+	for name, r := range reg.SupportedResources() {
+		name, r := name, r
+		t.Run("resource/"+name, func(t *testing.T) {
+			checkSchemaCompatibility(t, filepath.Join(schemaGoldenDir, "resources", name+".json"), r)
+		})
+	}
+
+	for name, r := range reg.SupportedDataSources() {
+		name, r := name, r
+		t.Run("data-source/"+name, func(t *testing.T) {
+			checkSchemaCompatibility(t, filepath.Join(schemaGoldenDir, "data-sources", name+".json"), r)
+		})
+	}
+}
+
+func checkSchemaCompatibility(t *testing.T, goldenPath string, r *schema.Resource) {
+	t.Helper()
+
+	current := snapshotResource(r)
+
+	data, err := os.ReadFile(goldenPath)
+	if os.IsNotExist(err) {
+		if !*updateSchemas {
+			t.Fatalf("no golden schema file %s, run the test with -update to create it", goldenPath)
+		}
+		writeSchemaSnapshot(t, goldenPath, current)
+
+		return
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var golden schemaSnapshot
+	if err = json.Unmarshal(data, &golden); err != nil {
+		t.Fatalf("error parsing %s: %s", goldenPath, err)
+	}
+
+	breaking := incompatibleChanges(golden, current)
+	if len(breaking) > 0 && !hasStateUpgrade(r, golden.SchemaVersion) {
+		for _, b := range breaking {
+			t.Error(b)
+		}
+		t.Fatalf("backward-incompatible schema changes, bump SchemaVersion from %d and add a StateUpgrader "+
+			"for version %d", golden.SchemaVersion, golden.SchemaVersion)
+	}
+
+	if *updateSchemas {
+		writeSchemaSnapshot(t, goldenPath, current)
+	}
+}
+
+// incompatibleChanges returns the changes between golden and current that break existing
+// configuration or state files
+func incompatibleChanges(golden, current schemaSnapshot) []string {
+	var changes []string
+
+	for key, old := range golden.Attributes {
+		cur, ok := current.Attributes[key]
+		if !ok {
+			changes = append(changes, fmt.Sprintf("%s: attribute removed", key))
+
+			continue
+		}
+
+		if old.Type != cur.Type || old.ElemType != cur.ElemType {
+			changes = append(changes, fmt.Sprintf("%s: type changed from %s%s to %s%s",
+				key, old.Type, elemSuffix(old.ElemType), cur.Type, elemSuffix(cur.ElemType)))
+		}
+
+		if !old.Required && cur.Required {
+			changes = append(changes, fmt.Sprintf("%s: attribute is now required", key))
+		}
+
+		if (old.Optional || old.Required) && !(cur.Optional || cur.Required) {
+			changes = append(changes, fmt.Sprintf("%s: attribute can no longer be set", key))
+		}
+
+		if !old.ForceNew && cur.ForceNew {
+			changes = append(changes, fmt.Sprintf("%s: attribute is now ForceNew", key))
+		}
+	}
+
+	for key, cur := range current.Attributes {
+		if _, ok := golden.Attributes[key]; ok || !cur.Required {
+			continue
+		}
+
+		// A required attribute inside a block that is itself new doesn't break existing configuration,
+		// which can't use the block yet, it only has to be set when the block is added.  If the new block
+		// is required it is reported on its own.
+		if newBlock(golden, key) {
+			continue
+		}
+
+		changes = append(changes, fmt.Sprintf("%s: new required attribute", key))
+	}
+
+	sort.Strings(changes)
+
+	return changes
+}
+
+// newBlock returns true if key is nested in a block that isn't in golden
+func newBlock(golden schemaSnapshot, key string) bool {
+	i := strings.LastIndex(key, ".")
+	if i < 0 {
+		return false
+	}
+
+	_, ok := golden.Attributes[key[:i]]
+
+	return !ok
+}
+
+// hasStateUpgrade checks that SchemaVersion has been bumped past version and that there is a
+// StateUpgrader that takes state at version forward
+func hasStateUpgrade(r *schema.Resource, version int) bool {
+	if r.SchemaVersion <= version {
+		return false
+	}
+
+	for _, upgrader := range r.StateUpgraders {
+		if upgrader.Version == version {
+			return true
+		}
+	}
+
+	return false
+}
+
+func snapshotResource(r *schema.Resource) schemaSnapshot {
+	snapshot := schemaSnapshot{
+		SchemaVersion: r.SchemaVersion,
+		Attributes:    make(map[string]attributeSnapshot),
+	}
+	snapshotSchemaMap(r.Schema, "", snapshot.Attributes)
+
+	return snapshot
+}
+
+func snapshotSchemaMap(m map[string]*schema.Schema, prefix string, out map[string]attributeSnapshot) {
+	for name, s := range m {
+		key := prefix + name
+		attr := attributeSnapshot{
+			Type:     s.Type.String(),
+			Required: s.Required,
+			Optional: s.Optional,
+			Computed: s.Computed,
+			ForceNew: s.ForceNew,
+		}
+
+		switch elem := s.Elem.(type) {
+		case *schema.Schema:
+			attr.ElemType = elem.Type.String()
+		case *schema.Resource:
+			snapshotSchemaMap(elem.Schema, key+".", out)
+		}
+
+		out[key] = attr
+	}
+}
+
+func writeSchemaSnapshot(t *testing.T, path string, snapshot schemaSnapshot) {
+	t.Helper()
+
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = os.WriteFile(path, append(data, '\n'), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func elemSuffix(elemType string) string {
+	if elemType == "" {
+		return ""
+	}
+
+	return "(" + elemType + ")"
+}
+
+func TestSchemaCompatibilityNewRequiredAttribute(t *testing.T) {
+	golden := schemaSnapshot{Attributes: map[string]attributeSnapshot{
+		"name":              {Type: "TypeString", Required: true},
+		"worker_nodes":      {Type: "TypeList", Optional: true},
+		"worker_nodes.name": {Type: "TypeString", Required: true},
+	}}
+
+	testCases := []struct {
+		name     string
+		added    map[string]attributeSnapshot
+		breaking []string
+	}{
+		{
+			name:  "optional attribute",
+			added: map[string]attributeSnapshot{"site_id": {Type: "TypeString", Optional: true}},
+		},
+		{
+			name:     "required attribute",
+			added:    map[string]attributeSnapshot{"site_id": {Type: "TypeString", Required: true}},
+			breaking: []string{"site_id: new required attribute"},
+		},
+		{
+			name:     "required attribute of an existing block",
+			added:    map[string]attributeSnapshot{"worker_nodes.count": {Type: "TypeInt", Required: true}},
+			breaking: []string{"worker_nodes.count: new required attribute"},
+		},
+		{
+			name: "required attribute of a new optional block",
+			added: map[string]attributeSnapshot{
+				"control_plane":       {Type: "TypeList", Optional: true},
+				"control_plane.count": {Type: "TypeInt", Required: true},
+			},
+		},
+		{
+			name: "new required block",
+			added: map[string]attributeSnapshot{
+				"control_plane":       {Type: "TypeList", Required: true},
+				"control_plane.count": {Type: "TypeInt", Required: true},
+			},
+			breaking: []string{"control_plane: new required attribute"},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			current := schemaSnapshot{Attributes: make(map[string]attributeSnapshot)}
+			for k, v := range golden.Attributes {
+				current.Attributes[k] = v
+			}
+			for k, v := range tc.added {
+				current.Attributes[k] = v
+			}
+
+			breaking := incompatibleChanges(golden, current)
+			if strings.Join(breaking, "\n") != strings.Join(tc.breaking, "\n") {
+				t.Errorf("got breaking changes %q, expected %q", breaking, tc.breaking)
+			}
+		})
+	}
+}
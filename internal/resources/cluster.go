@@ -4,7 +4,6 @@ package resources
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"net"
@@ -12,6 +11,7 @@ import (
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 
@@ -20,6 +20,8 @@ import (
 	"github.com/HewlettPackard/hpegl-containers-terraform-resources/internal/resources/schemas"
 	"github.com/HewlettPackard/hpegl-containers-terraform-resources/pkg/auth"
 	"github.com/HewlettPackard/hpegl-containers-terraform-resources/pkg/client"
+	"github.com/HewlettPackard/hpegl-containers-terraform-resources/pkg/constants"
+	"github.com/HewlettPackard/hpegl-containers-terraform-resources/pkg/credential"
 	"github.com/HewlettPackard/hpegl-containers-terraform-resources/pkg/utils"
 )
 
@@ -52,14 +54,26 @@ type getTokenFunc func() (string, error)
 // nolint: funlen
 func Cluster() *schema.Resource {
 	return &schema.Resource{
-		Schema:         schemas.Cluster(),
-		SchemaVersion:  0,
-		StateUpgraders: nil,
-		CreateContext:  clusterCreateContext,
-		ReadContext:    clusterReadContext,
-		UpdateContext:  clusterUpdateContext,
-		DeleteContext:  clusterDeleteContext,
-		CustomizeDiff:  nil,
+		Schema:        schemas.Cluster(),
+		SchemaVersion: 1,
+		StateUpgraders: []schema.StateUpgrader{
+			{
+				Type:    clusterV0().CoreConfigSchema().ImpliedType(),
+				Upgrade: clusterStateUpgradeV0,
+				Version: 0,
+			},
+		},
+		CreateContext: clusterCreateContext,
+		ReadContext:   clusterReadContext,
+		UpdateContext: clusterUpdateContext,
+		DeleteContext: clusterDeleteContext,
+		CustomizeDiff: customdiff.All(
+			customizeDiffProviderDefaults(constants.SiteID, constants.SpaceID),
+			customizeDiffWorkerNodes,
+			customizeDiffEffectiveMachineSets,
+			customizeDiffControlPlane,
+			customizeDiffCapacity,
+		),
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
@@ -71,13 +85,79 @@ func Cluster() *schema.Resource {
 		},
 		Description: `The cluster resource facilitates the creation, updation and
 			deletion of a CaaS cluster. There are four required inputs when 
-			creating a cluster - name, blueprint_id, site_id and space_id. 
+			creating a cluster - name, blueprint_id, site_id and space_id.
+			Changing any of these replaces the cluster.  site_id and space_id default to
+			the site_id and space_id of the caas provider block, the value used is kept in
+			state so changing the provider default also replaces the cluster.
 			worker_nodes is an optional input to scale nodes on cluster.
             Provide the min_size & max_size parameters to trigger Autoscaler.
-            Kubernetes version upgrade is also supported while updating the cluster.`,
+            Kubernetes version upgrade is also supported while updating the cluster.
+            Set wait_for_health to also wait until the cluster health is one of
+            acceptable_health (default ["ok"]) before create or update completes, and
+            wait_for_api_server to wait until the kubeconfig can be fetched and the
+            API server's /readyz endpoint answers.
+            Set wait_for_completion to false to return as soon as the API has accepted
+            a create, update or delete, the hpegl_caas_cluster_wait resource can then
+            be used to wait for the cluster to reach a given state.
+            The kubeconfig is available base64-encoded as kubeconfig, as YAML as
+            kubeconfig_raw, and decoded into kube_host, cluster_ca_certificate,
+            kube_token, client_certificate and client_key, all are sensitive.
+            Set store_kubeconfig to false to keep the kubeconfig out of state, including
+            encrypted_kubeconfig, and use the hpegl_caas_kubeconfig data source to fetch
+            it when it is needed.
+            Set pgp_key to keybase:<username> or a PGP public key (armored or base64)
+            to store the kubeconfig YAML only as encrypted_kubeconfig, base64-encoded
+            and encrypted to that key, with its fingerprint in key_fingerprint.  The
+            kubeconfig is only encrypted again when it or pgp_key changes, the SHA-256 of
+            both is kept in encrypted_kubeconfig_sha256.
+            exec_kubeconfig is a kubeconfig that holds no credentials, it runs the
+            hpegl-caas-credential exec plugin to get a short-lived token instead.
+            worker_nodes is refreshed from the cluster's machine sets, so pools that
+            are changed outside of terraform show up in the plan.  The autoscaler only
+            changes the number of machines in a pool, which isn't part of worker_nodes.
+            Set ignore_autoscaler_changes to send the current number of machines of
+            pools with min_size less than max_size with an update, kept within their
+            min_size and max_size, so that the update doesn't resize them.
+            A worker_nodes block named after one of the blueprint's default machine
+            sets overrides it, and removing the block reverts the machine set to the
+            blueprint values.  Other worker_nodes blocks add pools, removing the block
+            removes the pool.  effective_machine_sets shows the resulting machine sets.
+            The control_plane block scales the blueprint's control plane machine set to
+            count machines, which must be odd, and can move it to another
+            machine_blueprint_id.  The control plane machine blueprint is checked at plan
+            time against the min_master_size of the cluster provider.  Removing the
+            control_plane block leaves the control plane at its current size, it isn't
+            scaled back to the blueprint default.
+            Before an update is sent, the provider waits for any operation already in
+            progress on the cluster to finish and refuses the update if the cluster has
+            changed since it was last refreshed, so that changes made by the autoscaler or
+            in the portal are not overwritten.  Plan and apply again to pick them up.
+            Set capacity_check to warn or error to compare the nodes and cpus that a
+            create or scale-up adds with the available_capacity of the cluster provider
+            at plan time, the breakdown is shown in capacity_check_summary.  The nodes
+            added are counted from min_size, the nodes the autoscaler may add up to a
+            raised max_size are listed in the summary but not compared.  The summary is
+            empty when there is enough capacity or nothing is added.
+            Creates and updates fail if a license of the cluster provider is invalid, and
+            warn if one is expiring or degraded, has a status that isn't known, or if the
+            cluster provider can't be looked up.`,
+	}
+}
+
+// clusterV0 is the cluster resource at schema version 0, before name, blueprint_id, site_id
+// and space_id became ForceNew
+func clusterV0() *schema.Resource {
+	return &schema.Resource{
+		Schema: schemas.ClusterV0(),
 	}
 }
 
+// clusterStateUpgradeV0 upgrades state from version 0.  Only ForceNew changed, so the attributes
+// are kept as they are and the ones added since version 0 are left unset until the next refresh.
+func clusterStateUpgradeV0(ctx context.Context, rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+	return rawState, nil
+}
+
 func clusterCreateContext(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	c, err := client.GetClientFromMetaMap(meta)
 	if err != nil {
@@ -92,6 +172,18 @@ func clusterCreateContext(ctx context.Context, d *schema.ResourceData, meta inte
 	var diags diag.Diagnostics
 
 	spaceID := d.Get("space_id").(string)
+	waitForCompletion := d.Get("wait_for_completion").(bool)
+	start := time.Now()
+
+	blueprint, err := getClusterBlueprint(clientCtx, c, d.Get("site_id").(string), d.Get("blueprint_id").(string))
+	if err != nil {
+		diags = append(diags, licenseCheckSkipped(err)...)
+	} else {
+		diags = append(diags, checkClusterProviderLicense(clientCtx, c, d.Get("site_id").(string), blueprint.ClusterProvider)...)
+		if diags.HasError() {
+			return diags
+		}
+	}
 
 	createCluster := mcaasapi.CreateCluster{
 		Name:               d.Get("name").(string),
@@ -109,18 +201,27 @@ func clusterCreateContext(ctx context.Context, d *schema.ResourceData, meta inte
 	}
 	defer resp.Body.Close()
 
-	createStateConf := resource.StateChangeConf{
-		Delay:      0,
-		Pending:    []string{stateInitializing, stateProvisioning, stateCreating, stateRetrying},
-		Target:     []string{stateReady},
-		Timeout:    d.Timeout("create"),
-		MinTimeout: pollingInterval,
-		Refresh:    clusterRefresh(ctx, d, cluster.Id, spaceID, stateReady, meta),
-	}
+	workerNodes, workerNodePresent := d.GetOk("worker_nodes")
+	newK8sVersionInterface, k8sVersionPresent := d.GetOk("kubernetesVersion")
+	controlPlane, controlPlanePresent := d.GetOk("control_plane")
 
-	_, err = createStateConf.WaitForStateContext(ctx)
-	if err != nil {
-		return diag.FromErr(err)
+	// The cluster has to be ready before worker nodes can be added, so we wait here even if
+	// wait_for_completion is false
+	if waitForCompletion || workerNodePresent || k8sVersionPresent || controlPlanePresent {
+		createStateConf := resource.StateChangeConf{
+			Delay:        0,
+			Pending:      []string{stateInitializing, stateProvisioning, stateCreating, stateRetrying},
+			Target:       []string{stateReady},
+			Timeout:      d.Timeout("create"),
+			MinTimeout:   pollingInterval,
+			PollInterval: c.PollInterval,
+			Refresh:      clusterRefresh(ctx, d, "create", cluster.Id, spaceID, stateReady, meta),
+		}
+
+		_, err = createStateConf.WaitForStateContext(ctx)
+		if err != nil {
+			return diag.FromErr(err)
+		}
 	}
 
 	// Only set id to non-empty string if resource has been successfully created
@@ -139,35 +240,10 @@ func clusterCreateContext(ctx context.Context, d *schema.ResourceData, meta inte
 	}
 
 	//Add additional worker node pool after cluster creation
-	workerNodes, workerNodePresent := d.GetOk("worker_nodes")
-	newK8sVersionInterface, k8sVersionPresent := d.GetOk("kubernetesVersion")
-	if workerNodePresent || k8sVersionPresent {
-		workerNodesList := workerNodes.([]interface{})
-		machineSets := []mcaasapi.MachineSet{}
-
-		for _, workerNode := range workerNodesList {
-			machineSets = append(machineSets, getWorkerNodeDetails(workerNode.(map[string]interface{})))
-		}
-
-		defaultMachineSets := cluster.MachineSets
-		defaultWorkersName, err := GetDefaultWorkersName(d)
-		if err != nil {
-			return diag.FromErr(err)
-		}
-		//Remove default worker node if its declared in worker nodes
-		for _, defaultWorkerName := range defaultWorkersName {
-			if utils.WorkerPresentInMachineSets(machineSets, defaultWorkerName) {
-				defaultMachineSets = utils.RemoveWorkerFromMachineSets(defaultMachineSets, defaultWorkerName)
-			}
-		}
-
-		machineSets = append(defaultMachineSets, machineSets...)
-		temp, err := json.Marshal(machineSets)
-		if err != nil {
-			return diag.Errorf("Error in parsing machinesets response %s", err)
-		}
-		var finalMachineSets []mcaasapi.UpdateClusterMachineSet
-		_ = json.Unmarshal(temp, &finalMachineSets)
+	if workerNodePresent || k8sVersionPresent || controlPlanePresent {
+		machineSets := buildMachineSets(cluster.MachineSets, workerNodes.([]interface{}))
+		machineSets = applyControlPlane(machineSets, getControlPlaneName(cluster.MachineSetsDetail), controlPlane.([]interface{}))
+		finalMachineSets := toUpdateClusterMachineSets(machineSets)
 
 		//Check if kubernetesVersion update is present
 		newK8sVersion := ""
@@ -189,27 +265,39 @@ func clusterCreateContext(ctx context.Context, d *schema.ResourceData, meta inte
 		}
 		defer resp.Body.Close()
 
-		createStateConf := resource.StateChangeConf{
-			Delay:      0,
-			Pending:    []string{stateProvisioning, stateCreating, stateRetrying, stateUpdating, stateDeProvisioning, stateUpgrading},
-			Target:     []string{stateReady},
-			Timeout:    d.Timeout("create"),
-			MinTimeout: pollingInterval,
-			Refresh:    clusterRefresh(ctx, d, cluster.Id, spaceID, stateReady, meta),
+		if waitForCompletion {
+			createStateConf := resource.StateChangeConf{
+				Delay:        0,
+				Pending:      []string{stateProvisioning, stateCreating, stateRetrying, stateUpdating, stateDeProvisioning, stateUpgrading},
+				Target:       []string{stateReady},
+				Timeout:      d.Timeout("create"),
+				MinTimeout:   pollingInterval,
+				PollInterval: c.PollInterval,
+				Refresh:      clusterRefresh(ctx, d, "create-update", cluster.Id, spaceID, stateReady, meta),
+			}
+
+			_, err = createStateConf.WaitForStateContext(ctx)
+			if err != nil {
+				return diag.FromErr(err)
+			}
 		}
+	}
 
-		_, err = createStateConf.WaitForStateContext(ctx)
+	if waitForCompletion {
+		err = waitForClusterReadiness(ctx, d, c, d.Id(), spaceID, d.Timeout("create")-time.Since(start), meta)
 		if err != nil {
-			return diag.FromErr(err)
+			return diag.Errorf("Error waiting for cluster %s to become healthy: %s", d.Id(), err)
 		}
 	}
 
+	diags = append(diags, capacityWarning(d)...)
+
 	// TODO Should we be passing clientCtx here?
-	return clusterReadContext(ctx, d, meta)
+	return append(diags, clusterReadContext(ctx, d, meta)...)
 }
 
 func clusterRefresh(ctx context.Context, d *schema.ResourceData,
-	id, spaceID, expectedState string,
+	operation, id, spaceID, expectedState string,
 	meta interface{},
 ) resource.StateRefreshFunc {
 	c, err := client.GetClientFromMetaMap(meta)
@@ -218,7 +306,7 @@ func clusterRefresh(ctx context.Context, d *schema.ResourceData,
 	}
 
 	// Create getTokenFunc for execution in a closure that increments retry counters
-	gtf := createGetTokenFunc(ctx, c, id, spaceID, expectedState, meta)
+	gtf := createGetTokenFunc(ctx, c, id, spaceID, expectedState, meta, newClusterProgress(operation, id))
 
 	return func() (result interface{}, state string, err error) {
 		state, err = gtf()
@@ -252,12 +340,59 @@ func clusterReadContext(ctx context.Context, d *schema.ResourceData, meta interf
 		return diag.FromErr(err)
 	}
 
+	if err = writeDefaultMachineSets(clientCtx, c, d, &cluster); err != nil {
+		return diag.FromErr(err)
+	}
+
+	// Machine sets are only compared with worker_nodes once the cluster is ready, until then they may
+	// not reflect the last update
+	if cluster.State == stateReady {
+		if err = writeWorkerNodes(d, &cluster); err != nil {
+			return diag.FromErr(err)
+		}
+
+		if err = writeControlPlane(d, &cluster); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	if err = writeEffectiveMachineSets(d, &cluster); err != nil {
+		return diag.FromErr(err)
+	}
+
+	storeKubeconfig := d.Get("store_kubeconfig").(bool)
+
+	// The kubeconfig is only available once the cluster is ready, this will not be the case
+	// if wait_for_completion is false and the cluster is still being provisioned
+	if cluster.State != stateReady {
+		if !storeKubeconfig {
+			return diag.FromErr(clearStoredKubeconfig(d))
+		}
+
+		return diags
+	}
+
 	kubeconfig, _, err := c.CaasClient.KubeConfigApi.V1ClustersIdKubeconfigGet(clientCtx, id)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
-	if err = d.Set("kubeconfig", kubeconfig.Kubeconfig); err != nil {
+	// The exec-based kubeconfig holds no secrets, so it is always stored
+	execKubeconfig, err := credential.ExecKubeconfig(kubeconfig.Kubeconfig, id, c.APIURL)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err = d.Set("exec_kubeconfig", execKubeconfig); err != nil {
+		return diag.FromErr(err)
+	}
+
+	// Keep the kubeconfig out of state, the hpegl_caas_kubeconfig data source can be used instead
+	if !storeKubeconfig {
+		return diag.FromErr(clearStoredKubeconfig(d))
+	}
+
+	if err = writeClusterKubeconfig(ctx, d, kubeconfig.Kubeconfig); err != nil {
 		return diag.FromErr(err)
 	}
 
@@ -372,13 +507,25 @@ func clusterDeleteContext(ctx context.Context, d *schema.ResourceData, meta inte
 	}
 	defer resp.Body.Close()
 
+	if !d.Get("wait_for_completion").(bool) {
+		d.SetId("")
+
+		return diags
+	}
+
+	delay := pollingInterval
+	if c.PollInterval > 0 {
+		delay = c.PollInterval
+	}
+
 	deleteStateConf := resource.StateChangeConf{
-		Delay:      pollingInterval,
-		Pending:    []string{stateDeleting, stateRetrying},
-		Target:     []string{stateDeleted},
-		Timeout:    d.Timeout("delete"),
-		MinTimeout: pollingInterval,
-		Refresh:    clusterRefresh(ctx, d, id, spaceID, stateDeleted, meta),
+		Delay:        delay,
+		Pending:      []string{stateDeleting, stateRetrying},
+		Target:       []string{stateDeleted},
+		Timeout:      d.Timeout("delete"),
+		MinTimeout:   pollingInterval,
+		PollInterval: c.PollInterval,
+		Refresh:      clusterRefresh(ctx, d, "delete", id, spaceID, stateDeleted, meta),
 	}
 
 	_, err = deleteStateConf.WaitForStateContext(ctx)
@@ -395,12 +542,14 @@ func clusterDeleteContext(ctx context.Context, d *schema.ResourceData, meta inte
 
 // createGetTokenFunc is a closure that returns a getTokenFunc
 // The closure sets counters that are incremented on each execution of getTokenFunc
+// Each polled cluster is passed to progress which logs the progress of the operation
 // nolint cyclop
 func createGetTokenFunc(
 	ctx context.Context,
 	c *client.Client,
 	id, spaceID, expectedState string,
 	meta interface{},
+	progress *clusterProgress,
 ) getTokenFunc {
 	// We set these counters in the closure
 	noEntryInListRetryCount := 0
@@ -425,6 +574,8 @@ func createGetTokenFunc(
 				case http.StatusInternalServerError:
 					errRetryCount++
 					if errRetryCount < retryLimit {
+						progress.retrying(ctx, resp.Status, errRetryCount)
+
 						return stateRetrying, nil
 					}
 
@@ -433,6 +584,8 @@ func createGetTokenFunc(
 				case http.StatusGatewayTimeout:
 					errRetryCount++
 					if errRetryCount < retryLimit {
+						progress.retrying(ctx, resp.Status, errRetryCount)
+
 						return stateRetrying, nil
 					}
 
@@ -446,6 +599,8 @@ func createGetTokenFunc(
 			if isErrRetryable(err) {
 				errRetryCount++
 				if errRetryCount < retryLimit {
+					progress.retrying(ctx, err.Error(), errRetryCount)
+
 					return stateRetrying, nil
 				}
 			}
@@ -467,6 +622,8 @@ func createGetTokenFunc(
 		if cluster == nil {
 			switch expectedState {
 			case stateDeleted:
+				progress.deleted(ctx)
+
 				return stateDeleted, nil
 
 			default:
@@ -474,12 +631,14 @@ func createGetTokenFunc(
 				if noEntryInListRetryCount > retryLimit {
 					return "", errors.New("failed to find cluster in list")
 				}
+				progress.retrying(ctx, "cluster not found in list", noEntryInListRetryCount)
 
 				return stateRetrying, nil
 			}
 		}
 		// Reset noEntryInListRetryCount
 		noEntryInListRetryCount = 0
+		progress.update(ctx, cluster)
 
 		return cluster.State, nil
 	}
@@ -508,48 +667,34 @@ func clusterUpdateContext(ctx context.Context, d *schema.ResourceData, meta inte
 
 	clientCtx := context.WithValue(ctx, mcaasapi.ContextAccessToken, token)
 	var diags diag.Diagnostics
-	newK8sVersionInterface, k8sVersionPresent := d.GetOk("kubernetes_version")
+	start := time.Now()
 
-	if d.HasChange("worker_nodes") || k8sVersionPresent {
-		machineSets := []mcaasapi.MachineSet{}
-
-		workerNodes := d.Get("worker_nodes").([]interface{})
-		for _, workerNode := range workerNodes {
-			machineSets = append(machineSets, getWorkerNodeDetails(workerNode.(map[string]interface{})))
+	// Only changes to the machine sets or kubernetes version are sent to the API, the other arguments
+	// are local to the provider and only need the cluster to be re-read
+	if d.HasChange("worker_nodes") || d.HasChange("control_plane") || d.HasChange("kubernetes_version") {
+		var liveCluster *mcaasapi.Cluster
+		if liveCluster, diags = checkClusterUnchanged(ctx, d, c, clientCtx, meta); diags.HasError() {
+			return diags
 		}
 
-		defaultMachineSetsInterface := d.Get("default_machine_sets").([]interface{})
-		defaultMachineSets := []mcaasapi.MachineSet{}
-
-		for _, dms := range defaultMachineSetsInterface {
-			defaultMachineSet := getDefaultMachineSet(dms.(map[string]interface{}))
-			defaultMachineSets = append(defaultMachineSets, defaultMachineSet)
-		}
-		defaultWorkersName, err := GetDefaultWorkersName(d)
-		if err != nil {
-			return diag.FromErr(err)
-		}
-		for _, defaultWorkerName := range defaultWorkersName {
-			if utils.WorkerPresentInMachineSets(machineSets, defaultWorkerName) {
-				defaultMachineSets = utils.RemoveWorkerFromMachineSets(defaultMachineSets, defaultWorkerName)
-			}
-		}
-		machineSets = append(machineSets, defaultMachineSets...)
-		temp, err := json.Marshal(machineSets)
-		if err != nil {
-			return diag.Errorf("Error in parsing machinesets response %s", err)
-		}
-		var finalMachineSets []mcaasapi.UpdateClusterMachineSet
-		_ = json.Unmarshal(temp, &finalMachineSets)
-		//Check if kubernetesVersion update is present
-		newK8sVersion := ""
-		if k8sVersionPresent {
-			newK8sVersion = fmt.Sprintf("%v", newK8sVersionInterface)
+		diags = append(diags, checkClusterProviderLicense(clientCtx, c, d.Get("site_id").(string), d.Get("cluster_provider").(string))...)
+		if diags.HasError() {
+			return diags
 		}
 
+		defaultMachineSets := getDefaultMachineSets(d.Get("default_machine_sets").([]interface{}))
+		machineSets := buildMachineSets(defaultMachineSets, d.Get("worker_nodes").([]interface{}))
+		controlPlaneName := getDefaultControlPlaneName(d.Get("default_machine_sets_detail").([]interface{}))
+		priorEffective, _ := d.GetChange("effective_machine_sets")
+		controlPlane := keepControlPlane(d.Get("control_plane").([]interface{}), priorEffective.([]interface{}), controlPlaneName)
+		machineSets = applyControlPlane(machineSets, controlPlaneName, controlPlane)
+		if d.Get("ignore_autoscaler_changes").(bool) {
+			machineSets = keepAutoscaledCounts(machineSets, liveCluster)
+		}
+		finalMachineSets := toUpdateClusterMachineSets(machineSets)
 		updateCluster := mcaasapi.UpdateCluster{
 			MachineSets:       finalMachineSets,
-			KubernetesVersion: newK8sVersion,
+			KubernetesVersion: d.Get("kubernetes_version").(string),
 		}
 		clusterID := d.Id()
 		cluster, resp, err := c.CaasClient.ClustersApi.V1ClustersIdPut(clientCtx, updateCluster, clusterID)
@@ -560,23 +705,37 @@ func clusterUpdateContext(ctx context.Context, d *schema.ResourceData, meta inte
 		}
 		defer resp.Body.Close()
 
+		if !d.Get("wait_for_completion").(bool) {
+			diags = append(diags, capacityWarning(d)...)
+
+			return append(diags, clusterReadContext(ctx, d, meta)...)
+		}
+
 		spaceID := d.Get("space_id").(string)
 		createStateConf := resource.StateChangeConf{
-			Delay:      0,
-			Pending:    []string{stateProvisioning, stateCreating, stateRetrying, stateUpdating, stateDeProvisioning, stateUpgrading},
-			Target:     []string{stateReady},
-			Timeout:    d.Timeout("create"),
-			MinTimeout: pollingInterval,
-			Refresh:    clusterRefresh(ctx, d, cluster.Id, spaceID, stateReady, meta),
+			Delay:        0,
+			Pending:      []string{stateProvisioning, stateCreating, stateRetrying, stateUpdating, stateDeProvisioning, stateUpgrading},
+			Target:       []string{stateReady},
+			Timeout:      d.Timeout("update"),
+			MinTimeout:   pollingInterval,
+			PollInterval: c.PollInterval,
+			Refresh:      clusterRefresh(ctx, d, "update", cluster.Id, spaceID, stateReady, meta),
 		}
 
 		_, err = createStateConf.WaitForStateContext(ctx)
 		if err != nil {
 			return diag.FromErr(err)
 		}
+
+		err = waitForClusterReadiness(ctx, d, c, cluster.Id, spaceID, d.Timeout("update")-time.Since(start), meta)
+		if err != nil {
+			return diag.Errorf("Error waiting for cluster %s to become healthy: %s", cluster.Id, err)
+		}
 	}
 
-	return clusterReadContext(ctx, d, meta)
+	diags = append(diags, capacityWarning(d)...)
+
+	return append(diags, clusterReadContext(ctx, d, meta)...)
 }
 
 func getDefaultMachineSet(defaultMachineSet map[string]interface{}) mcaasapi.MachineSet {
@@ -588,48 +747,3 @@ func getDefaultMachineSet(defaultMachineSet map[string]interface{}) mcaasapi.Mac
 	}
 	return wn
 }
-func getDefaultMachineSetDetail(defaultMachineSetDetail map[string]interface{}) mcaasapi.MachineSetDetail {
-	mr := defaultMachineSetDetail["machine_roles"].([]interface{})
-	MachineRoles := make([]mcaasapi.MachineRolesType, 0, len(mr))
-	for _, v := range mr {
-		MachineRoles = append(MachineRoles, mcaasapi.MachineRolesType(v.(string)))
-	}
-	macProvider := defaultMachineSetDetail["machine_provider"]
-	machineProvider := mcaasapi.MachineProviderName(macProvider.(string))
-	wnd := mcaasapi.MachineSetDetail{
-		Name:                defaultMachineSetDetail["name"].(string),
-		MinSize:             int32(defaultMachineSetDetail["min_size"].(float64)),
-		MaxSize:             int32(defaultMachineSetDetail["max_size"].(float64)),
-		MachineRoles:        MachineRoles,
-		MachineProvider:     &machineProvider,
-		Size:                defaultMachineSetDetail["size"].(string),
-		ComputeInstanceType: defaultMachineSetDetail["compute_type"].(string),
-		StorageInstanceType: defaultMachineSetDetail["storage_type"].(string),
-	}
-	return wnd
-}
-
-func GetDefaultWorkersName(d *schema.ResourceData) ([]string, error) {
-
-	defaultMachineSetsDetailInterface := d.Get("default_machine_sets_detail").([]interface{})
-	defaultMachineSetsDetail := []mcaasapi.MachineSetDetail{}
-
-	for _, dmsd := range defaultMachineSetsDetailInterface {
-		defaultMachineSetDetail := getDefaultMachineSetDetail(dmsd.(map[string]interface{}))
-		defaultMachineSetsDetail = append(defaultMachineSetsDetail, defaultMachineSetDetail)
-	}
-
-	var workerNames []string
-
-	for _, msd := range defaultMachineSetsDetail {
-		for _, role := range msd.MachineRoles {
-			if role == "worker" {
-				workerNames = append(workerNames, msd.Name)
-			}
-		}
-	}
-	if len(workerNames) == 0 {
-		return nil, fmt.Errorf("Worker node not present in the cluster")
-	}
-	return workerNames, nil
-}
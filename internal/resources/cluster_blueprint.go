@@ -8,6 +8,7 @@ import (
 	"github.com/HewlettPackard/hpegl-containers-terraform-resources/internal/resources/schemas"
 	"github.com/HewlettPackard/hpegl-containers-terraform-resources/pkg/auth"
 	"github.com/HewlettPackard/hpegl-containers-terraform-resources/pkg/client"
+	"github.com/HewlettPackard/hpegl-containers-terraform-resources/pkg/constants"
 	"github.com/HewlettPackard/hpegl-containers-terraform-resources/pkg/utils"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -23,14 +24,17 @@ func ClusterBlueprint() *schema.Resource {
 		// TODO figure out if and how a blueprint can be updated
 		// Update:             clusterBlueprintUpdate,
 		DeleteContext:      clusterBlueprintDeleteContext,
-		CustomizeDiff:      nil,
+		CustomizeDiff:      customizeDiffProviderDefaults(constants.SiteID),
 		Importer:           nil,
 		DeprecationMessage: "",
 		Timeouts:           nil,
 		Description: `The cluster blueprint resource facilitates the creation and
 			deletion of a CaaS cluster blueprint.  Update is currently not supported. The
 			required inputs when creating a cluster blueprint are name, kubernetes_version,
-			site-id, cluster_provider, control_plane, worker_nodes and default_storage_class`,
+			site-id, cluster_provider, control_plane, worker_nodes and default_storage_class.
+			The create fails if a license of the cluster provider is invalid, and warns if
+			one is expiring or degraded or has a status that isn't known.  site_id defaults
+			to the site_id of the caas provider block.`,
 	}
 }
 
@@ -48,6 +52,11 @@ func clusterBlueprintCreateContext(ctx context.Context, d *schema.ResourceData,
 	var diags diag.Diagnostics
 	var machineSetsList []mcaasapi.MachineSet
 
+	diags = append(diags, checkClusterProviderLicense(clientCtx, c, d.Get("site_id").(string), d.Get("cluster_provider").(string))...)
+	if diags.HasError() {
+		return diags
+	}
+
 	workerNodesList := d.Get("worker_nodes").([]interface{})
 	for _, workerNode := range workerNodesList {
 		worker, ok := workerNode.(map[string]interface{})
@@ -78,7 +87,7 @@ func clusterBlueprintCreateContext(ctx context.Context, d *schema.ResourceData,
 
 	d.SetId(clusterBlueprint.Id)
 
-	return clusterBlueprintReadContext(ctx, d, meta)
+	return append(diags, clusterBlueprintReadContext(ctx, d, meta)...)
 }
 
 func clusterBlueprintReadContext(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
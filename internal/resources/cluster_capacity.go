@@ -0,0 +1,224 @@
+// (C) Copyright 2023 Hewlett Packard Enterprise Development LP
+
+package resources
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/HewlettPackard/hpegl-containers-go-sdk/pkg/mcaasapi"
+
+	"github.com/HewlettPackard/hpegl-containers-terraform-resources/pkg/auth"
+	"github.com/HewlettPackard/hpegl-containers-terraform-resources/pkg/client"
+)
+
+const (
+	capacityCheckOff   = "off"
+	capacityCheckWarn  = "warn"
+	capacityCheckError = "error"
+)
+
+// capacityUsage is the number of nodes and cpus used by a set of machine sets
+type capacityUsage struct {
+	nodes int32
+	cpu   int32
+}
+
+// customizeDiffCapacity compares the nodes and cpus that the planned machine sets add to the site with
+// the available_capacity of the cluster provider.  The breakdown is shown in capacity_check_summary when
+// there isn't enough capacity, and the plan fails if capacity_check is error.
+// nolint: cyclop
+func customizeDiffCapacity(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	capacityCheck := d.Get("capacity_check").(string)
+	if capacityCheck == capacityCheckOff {
+		if d.Get("capacity_check_summary").(string) != "" {
+			return d.SetNew("capacity_check_summary", "")
+		}
+
+		return nil
+	}
+
+	// Nothing is added to an existing cluster unless its machine sets change, an earlier summary no
+	// longer applies
+	if d.Id() != "" && !d.HasChange("worker_nodes") && !d.HasChange("control_plane") && !d.HasChange("capacity_check") {
+		if d.Get("capacity_check_summary").(string) != "" {
+			return d.SetNew("capacity_check_summary", "")
+		}
+
+		return nil
+	}
+
+	if !d.NewValueKnown("worker_nodes") || !d.NewValueKnown("control_plane") ||
+		!d.NewValueKnown("site_id") || !d.NewValueKnown("blueprint_id") {
+		return d.SetNewComputed("capacity_check_summary")
+	}
+
+	c, err := client.GetClientFromMetaMap(meta)
+	if err != nil {
+		return err
+	}
+	token, err := auth.GetToken(ctx, meta)
+	if err != nil {
+		return fmt.Errorf("error in getting token: %w", err)
+	}
+	clientCtx := context.WithValue(ctx, mcaasapi.ContextAccessToken, token)
+
+	siteID := d.Get("site_id").(string)
+	clusterProviderName := d.Get("cluster_provider").(string)
+	defaults := getDefaultMachineSets(d.Get("default_machine_sets").([]interface{}))
+	controlPlaneName := getDefaultControlPlaneName(d.Get("default_machine_sets_detail").([]interface{}))
+
+	// The defaults come from the cluster blueprint when creating
+	if d.Id() == "" || len(defaults) == 0 {
+		blueprint, err := getClusterBlueprint(clientCtx, c, siteID, d.Get("blueprint_id").(string))
+		if err != nil {
+			return err
+		}
+
+		clusterProviderName = blueprint.ClusterProvider
+		defaults = blueprint.MachineSets
+		controlPlaneName = getControlPlaneName(blueprint.MachineSetsDetail)
+	}
+
+	planned := buildMachineSets(defaults, d.Get("worker_nodes").([]interface{}))
+	planned = applyControlPlane(planned, controlPlaneName, d.Get("control_plane").([]interface{}))
+
+	// An existing cluster already uses the capacity of its current machine sets
+	var current []mcaasapi.MachineSet
+	if d.Id() != "" {
+		for _, ms := range d.Get("machine_sets").([]interface{}) {
+			current = append(current, getDefaultMachineSet(ms.(map[string]interface{})))
+		}
+	}
+
+	clusterProvider, err := getClusterProvider(clientCtx, c, siteID, clusterProviderName)
+	if err != nil {
+		return err
+	}
+	if clusterProvider == nil || clusterProvider.AvailableCapacity == nil {
+		tflog.Warn(ctx, "no available capacity reported for cluster provider, skipping capacity check", map[string]interface{}{
+			"cluster_provider": clusterProviderName,
+		})
+
+		return d.SetNew("capacity_check_summary", "")
+	}
+
+	cpus := make(map[string]int32)
+	for _, ms := range append(append([]mcaasapi.MachineSet{}, planned...), current...) {
+		if _, ok := cpus[ms.MachineBlueprintId]; ok || ms.MachineBlueprintId == "" {
+			continue
+		}
+
+		machineBlueprint, err := getMachineBlueprint(clientCtx, c, siteID, ms.MachineBlueprintId)
+		if err != nil {
+			return err
+		}
+
+		if machineBlueprint.SizeDetail != nil {
+			cpus[ms.MachineBlueprintId] = machineBlueprint.SizeDetail.Cpu
+		}
+	}
+
+	summary, exceeded := capacityBreakdown(planned, current, cpus, clusterProvider.AvailableCapacity, d.Id() == "")
+	if !exceeded {
+		return d.SetNew("capacity_check_summary", "")
+	}
+
+	if capacityCheck == capacityCheckError {
+		return fmt.Errorf("cluster provider %s does not have enough available capacity:\n%s", clusterProviderName, summary)
+	}
+
+	tflog.Warn(ctx, "cluster provider does not have enough available capacity", map[string]interface{}{
+		"cluster_provider": clusterProviderName,
+		"summary":          summary,
+	})
+
+	return d.SetNew("capacity_check_summary", summary)
+}
+
+// capacityBreakdown returns a breakdown of the capacity needed by the planned machine sets, over and
+// above the current machine sets, and whether it exceeds the available capacity.  Only min_size
+// machines are created by the create or update, so the required capacity is counted from min_size.
+// The machines that the autoscaler may add later, up to a raised max_size, are listed separately and
+// aren't compared with the available capacity.
+func capacityBreakdown(
+	planned, current []mcaasapi.MachineSet,
+	cpus map[string]int32,
+	available *mcaasapi.ClusterProviderAvailableCapacity,
+	create bool,
+) (string, bool) {
+	var lines []string
+	var required, autoscaled capacityUsage
+
+	currentByName := make(map[string]mcaasapi.MachineSet)
+	for _, ms := range current {
+		currentByName[ms.Name] = ms
+	}
+
+	for _, ms := range planned {
+		cur := currentByName[ms.Name]
+		if cur.MachineBlueprintId != ms.MachineBlueprintId {
+			// Machines are replaced when the blueprint changes, the old ones are only released afterwards
+			cur = mcaasapi.MachineSet{}
+		}
+
+		nodes := ms.MinSize - cur.MinSize
+		if nodes < 0 {
+			nodes = 0
+		}
+		extra := ms.MaxSize - cur.MaxSize - nodes
+		if extra < 0 {
+			extra = 0
+		}
+
+		if nodes > 0 {
+			cpu := nodes * cpus[ms.MachineBlueprintId]
+			required.nodes += nodes
+			required.cpu += cpu
+			lines = append(lines, fmt.Sprintf("  %s: %d node(s) x %d cpu = %d cpu", ms.Name, nodes, cpus[ms.MachineBlueprintId], cpu))
+		}
+
+		if extra > 0 {
+			cpu := extra * cpus[ms.MachineBlueprintId]
+			autoscaled.nodes += extra
+			autoscaled.cpu += cpu
+			lines = append(lines, fmt.Sprintf("  %s: up to %d more node(s) x %d cpu = %d cpu if autoscaled to max_size",
+				ms.Name, extra, cpus[ms.MachineBlueprintId], cpu))
+		}
+	}
+
+	exceeded := float64(required.nodes) > available.Nodes || float64(required.cpu) > available.Cpu
+	if create && available.Clusters < 1 {
+		exceeded = true
+	}
+
+	lines = append(lines, fmt.Sprintf("  required: %d node(s), %d cpu", required.nodes, required.cpu))
+	if autoscaled.nodes > 0 {
+		lines = append(lines, fmt.Sprintf("  autoscaler: up to %d more node(s), %d cpu, not included in required",
+			autoscaled.nodes, autoscaled.cpu))
+	}
+	lines = append(lines,
+		fmt.Sprintf("  available: %g node(s), %g cpu, %g cluster(s)", available.Nodes, available.Cpu, available.Clusters))
+
+	return strings.Join(lines, "\n"), exceeded
+}
+
+// capacityWarning returns a warning with capacity_check_summary when capacity_check is warn and the
+// plan found that there isn't enough available capacity
+func capacityWarning(d *schema.ResourceData) diag.Diagnostics {
+	summary := d.Get("capacity_check_summary").(string)
+	if d.Get("capacity_check").(string) != capacityCheckWarn || summary == "" {
+		return nil
+	}
+
+	return diag.Diagnostics{{
+		Severity: diag.Warning,
+		Summary:  "Cluster provider may not have enough available capacity",
+		Detail:   summary,
+	}}
+}
@@ -0,0 +1,119 @@
+// (C) Copyright 2023 Hewlett Packard Enterprise Development LP
+
+package resources
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+
+	"github.com/HewlettPackard/hpegl-containers-go-sdk/pkg/mcaasapi"
+)
+
+func TestCapacityBreakdown(t *testing.T) {
+	cpus := map[string]int32{"small-bp": 2, "large-bp": 8}
+	current := []mcaasapi.MachineSet{
+		{Name: "worker", MachineBlueprintId: "small-bp", MinSize: 2, MaxSize: 4},
+	}
+
+	testCases := []struct {
+		name      string
+		planned   []mcaasapi.MachineSet
+		available mcaasapi.ClusterProviderAvailableCapacity
+		exceeded  bool
+		lines     []string
+	}{
+		{
+			name:      "nothing added",
+			planned:   current,
+			available: mcaasapi.ClusterProviderAvailableCapacity{},
+			lines:     []string{"required: 0 node(s), 0 cpu"},
+		},
+		{
+			name:      "min_size raised",
+			planned:   []mcaasapi.MachineSet{{Name: "worker", MachineBlueprintId: "small-bp", MinSize: 5, MaxSize: 5}},
+			available: mcaasapi.ClusterProviderAvailableCapacity{Nodes: 2, Cpu: 100},
+			exceeded:  true,
+			lines:     []string{"worker: 3 node(s) x 2 cpu = 6 cpu", "required: 3 node(s), 6 cpu"},
+		},
+		{
+			// Raising only max_size adds nothing now, the autoscaler headroom is listed but not compared
+			name:      "max_size raised",
+			planned:   []mcaasapi.MachineSet{{Name: "worker", MachineBlueprintId: "small-bp", MinSize: 2, MaxSize: 10}},
+			available: mcaasapi.ClusterProviderAvailableCapacity{Nodes: 1, Cpu: 1},
+			lines: []string{
+				"worker: up to 6 more node(s) x 2 cpu = 12 cpu if autoscaled to max_size",
+				"required: 0 node(s), 0 cpu",
+				"autoscaler: up to 6 more node(s), 12 cpu, not included in required",
+			},
+		},
+		{
+			name:      "blueprint changed",
+			planned:   []mcaasapi.MachineSet{{Name: "worker", MachineBlueprintId: "large-bp", MinSize: 2, MaxSize: 4}},
+			available: mcaasapi.ClusterProviderAvailableCapacity{Nodes: 10, Cpu: 10},
+			exceeded:  true,
+			lines: []string{
+				"worker: 2 node(s) x 8 cpu = 16 cpu",
+				"worker: up to 2 more node(s) x 8 cpu = 16 cpu if autoscaled to max_size",
+			},
+		},
+		{
+			name: "pool added",
+			planned: append(append([]mcaasapi.MachineSet{}, current...),
+				mcaasapi.MachineSet{Name: "pool", MachineBlueprintId: "small-bp", MinSize: 1, MaxSize: 1}),
+			available: mcaasapi.ClusterProviderAvailableCapacity{Nodes: 1, Cpu: 2},
+			lines:     []string{"pool: 1 node(s) x 2 cpu = 2 cpu", "required: 1 node(s), 2 cpu"},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			available := tc.available
+			summary, exceeded := capacityBreakdown(tc.planned, current, cpus, &available, false)
+
+			if exceeded != tc.exceeded {
+				t.Errorf("exceeded is %t, expected %t:\n%s", exceeded, tc.exceeded, summary)
+			}
+
+			for _, line := range tc.lines {
+				if !strings.Contains(summary, "  "+line+"\n") {
+					t.Errorf("summary has no line %q:\n%s", line, summary)
+				}
+			}
+		})
+	}
+}
+
+func TestCapacityCheckSummaryCleared(t *testing.T) {
+	state := &terraform.InstanceState{
+		ID: "5d1b7f5e-2b0e-4c6a-9d5f-8f0f3c8e1a27",
+		Attributes: map[string]string{
+			"id":                     "5d1b7f5e-2b0e-4c6a-9d5f-8f0f3c8e1a27",
+			"name":                   "test",
+			"blueprint_id":           "3f31daa9-9777-4c06-a4d0-e49215f5e48c",
+			"site_id":                "233eead2-20de-47ab-b266-2413cdaa3685",
+			"space_id":               "f866c9bd-2d2c-4e60-aab0-64737df96273",
+			"capacity_check":         capacityCheckWarn,
+			"capacity_check_summary": "  required: 3 node(s), 6 cpu",
+		},
+	}
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"name":           "test",
+		"blueprint_id":   "3f31daa9-9777-4c06-a4d0-e49215f5e48c",
+		"site_id":        "233eead2-20de-47ab-b266-2413cdaa3685",
+		"space_id":       "f866c9bd-2d2c-4e60-aab0-64737df96273",
+		"capacity_check": capacityCheckWarn,
+	})
+
+	diff, err := Cluster().Diff(context.Background(), state, config, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff == nil || diff.Attributes["capacity_check_summary"] == nil || diff.Attributes["capacity_check_summary"].New != "" {
+		t.Errorf("capacity_check_summary is not cleared when nothing is added: %v", diff)
+	}
+}
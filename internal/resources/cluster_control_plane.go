@@ -0,0 +1,195 @@
+// (C) Copyright 2023 Hewlett Packard Enterprise Development LP
+
+package resources
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/HewlettPackard/hpegl-containers-go-sdk/pkg/mcaasapi"
+
+	"github.com/HewlettPackard/hpegl-containers-terraform-resources/pkg/auth"
+	"github.com/HewlettPackard/hpegl-containers-terraform-resources/pkg/client"
+)
+
+// getControlPlaneName returns the name of the control plane machine set in machineSetsDetail
+func getControlPlaneName(machineSetsDetail []mcaasapi.MachineSetDetail) string {
+	for i := range machineSetsDetail {
+		if isControlPlaneMachineSet(&machineSetsDetail[i]) {
+			return machineSetsDetail[i].Name
+		}
+	}
+
+	return ""
+}
+
+// getDefaultControlPlaneName returns the name of the control plane machine set in default_machine_sets_detail
+func getDefaultControlPlaneName(defaultMachineSetsDetail []interface{}) string {
+	details := make([]mcaasapi.MachineSetDetail, 0, len(defaultMachineSetsDetail))
+	for _, dmsd := range defaultMachineSetsDetail {
+		dmsdMap := dmsd.(map[string]interface{})
+		msd := mcaasapi.MachineSetDetail{Name: dmsdMap["name"].(string)}
+		for _, role := range dmsdMap["machine_roles"].([]interface{}) {
+			msd.MachineRoles = append(msd.MachineRoles, mcaasapi.MachineRolesType(role.(string)))
+		}
+		details = append(details, msd)
+	}
+
+	return getControlPlaneName(details)
+}
+
+// applyControlPlane applies the control_plane block to the control plane machine set called name
+func applyControlPlane(machineSets []mcaasapi.MachineSet, name string, controlPlane []interface{}) []mcaasapi.MachineSet {
+	if len(controlPlane) == 0 || controlPlane[0] == nil || name == "" {
+		return machineSets
+	}
+
+	cp := controlPlane[0].(map[string]interface{})
+	count := int32(cp["count"].(int))
+	blueprintID := cp["machine_blueprint_id"].(string)
+
+	for i := range machineSets {
+		if machineSets[i].Name != name {
+			continue
+		}
+
+		machineSets[i].Count = count
+		machineSets[i].MinSize = count
+		machineSets[i].MaxSize = count
+		if blueprintID != "" && blueprintID != machineSets[i].MachineBlueprintId {
+			machineSets[i].MachineBlueprintId = blueprintID
+			machineSets[i].MachineBlueprintName = ""
+		}
+	}
+
+	return machineSets
+}
+
+// keepControlPlane returns controlPlane if it is declared, otherwise it returns a control_plane block that
+// keeps the control plane machine set called name at the size and machine blueprint it has in
+// effective, the prior effective_machine_sets.  This stops the removal of the control_plane block from
+// scaling the control plane back to the blueprint default, e.g. from 3 machines to 1.
+func keepControlPlane(controlPlane, effective []interface{}, name string) []interface{} {
+	if len(controlPlane) > 0 && controlPlane[0] != nil {
+		return controlPlane
+	}
+
+	for _, ms := range effective {
+		msMap, ok := ms.(map[string]interface{})
+		if !ok || name == "" || msMap["name"].(string) != name {
+			continue
+		}
+
+		return []interface{}{
+			map[string]interface{}{
+				"count":                int(msMap["min_size"].(float64)),
+				"machine_blueprint_id": msMap["machine_blueprint_id"].(string),
+			},
+		}
+	}
+
+	return controlPlane
+}
+
+// writeControlPlane refreshes the control_plane block from the live control plane machine set, it is
+// only set if control_plane is declared
+func writeControlPlane(d *schema.ResourceData, cluster *mcaasapi.Cluster) error {
+	if len(d.Get("control_plane").([]interface{})) == 0 {
+		return nil
+	}
+
+	name := getControlPlaneName(cluster.MachineSetsDetail)
+	for _, ms := range cluster.MachineSets {
+		if ms.Name != name {
+			continue
+		}
+
+		return d.Set("control_plane", []interface{}{
+			map[string]interface{}{
+				"count":                int(ms.MinSize),
+				"machine_blueprint_id": ms.MachineBlueprintId,
+			},
+		})
+	}
+
+	return nil
+}
+
+// customizeDiffControlPlane checks that the control plane machine blueprint is at least the
+// min_master_size of the cluster provider
+func customizeDiffControlPlane(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if !d.HasChange("control_plane") || len(d.Get("control_plane").([]interface{})) == 0 {
+		return nil
+	}
+
+	if !d.NewValueKnown("control_plane") || !d.NewValueKnown("site_id") || !d.NewValueKnown("blueprint_id") {
+		return nil
+	}
+
+	c, err := client.GetClientFromMetaMap(meta)
+	if err != nil {
+		return err
+	}
+	token, err := auth.GetToken(ctx, meta)
+	if err != nil {
+		return fmt.Errorf("error in getting token: %w", err)
+	}
+	clientCtx := context.WithValue(ctx, mcaasapi.ContextAccessToken, token)
+
+	siteID := d.Get("site_id").(string)
+	cp := d.Get("control_plane").([]interface{})[0].(map[string]interface{})
+	machineBlueprintID := cp["machine_blueprint_id"].(string)
+	clusterProviderName := d.Get("cluster_provider").(string)
+
+	// The cluster provider and default control plane blueprint come from the cluster blueprint when creating
+	if clusterProviderName == "" || machineBlueprintID == "" {
+		blueprint, err := getClusterBlueprint(clientCtx, c, siteID, d.Get("blueprint_id").(string))
+		if err != nil {
+			return err
+		}
+
+		if clusterProviderName == "" {
+			clusterProviderName = blueprint.ClusterProvider
+		}
+
+		if machineBlueprintID == "" {
+			name := getControlPlaneName(blueprint.MachineSetsDetail)
+			for _, ms := range blueprint.MachineSets {
+				if ms.Name == name {
+					machineBlueprintID = ms.MachineBlueprintId
+				}
+			}
+		}
+	}
+
+	clusterProvider, err := getClusterProvider(clientCtx, c, siteID, clusterProviderName)
+	if err != nil {
+		return err
+	}
+
+	if clusterProvider == nil || clusterProvider.MinMasterSize == nil || machineBlueprintID == "" {
+		return nil
+	}
+	minMasterSize := clusterProvider.MinMasterSize
+
+	machineBlueprint, err := getMachineBlueprint(clientCtx, c, siteID, machineBlueprintID)
+	if err != nil {
+		return err
+	}
+
+	size := machineBlueprint.SizeDetail
+	if size == nil {
+		return nil
+	}
+
+	if size.Cpu < minMasterSize.Cpu || size.Memory < minMasterSize.Memory || size.RootDisk < minMasterSize.RootDisk {
+		return fmt.Errorf("control plane machine blueprint %s (%s: cpu %d, memory %d, root disk %d) is smaller than "+
+			"the min_master_size of cluster provider %s (%s: cpu %d, memory %d, root disk %d)",
+			machineBlueprint.Name, size.Name, size.Cpu, size.Memory, size.RootDisk,
+			clusterProviderName, minMasterSize.Name, minMasterSize.Cpu, minMasterSize.Memory, minMasterSize.RootDisk)
+	}
+
+	return nil
+}
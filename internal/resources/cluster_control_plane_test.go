@@ -0,0 +1,72 @@
+// (C) Copyright 2023 Hewlett Packard Enterprise Development LP
+
+package resources
+
+import (
+	"context"
+	"testing"
+)
+
+const (
+	masterBlueprintID = "4a6f2c1e-8b3d-4f7a-a5e9-2d0c6b8f1e35"
+	workerBlueprintID = "c7e1d9b2-5a4f-4c8e-8b3a-9f6e2d1c0a78"
+)
+
+// TestClusterControlPlaneRemoved checks that removing the control_plane block keeps the control plane
+// at its current size rather than scaling it back to the blueprint default
+func TestClusterControlPlaneRemoved(t *testing.T) {
+	r := Cluster()
+
+	state := clusterTestState()
+	for k, v := range map[string]string{
+		"default_machine_sets.#":                      "2",
+		"default_machine_sets.0.name":                 "master",
+		"default_machine_sets.0.machine_blueprint_id": masterBlueprintID,
+		"default_machine_sets.0.min_size":             "1",
+		"default_machine_sets.0.max_size":             "1",
+		"default_machine_sets.1.name":                 "worker",
+		"default_machine_sets.1.machine_blueprint_id": workerBlueprintID,
+		"default_machine_sets.1.min_size":             "1",
+		"default_machine_sets.1.max_size":             "3",
+
+		"default_machine_sets_detail.#":                 "2",
+		"default_machine_sets_detail.0.name":            "master",
+		"default_machine_sets_detail.0.machine_roles.#": "2",
+		"default_machine_sets_detail.0.machine_roles.0": "controlplane",
+		"default_machine_sets_detail.0.machine_roles.1": "etcd",
+		"default_machine_sets_detail.1.name":            "worker",
+		"default_machine_sets_detail.1.machine_roles.#": "1",
+		"default_machine_sets_detail.1.machine_roles.0": "worker",
+
+		"effective_machine_sets.#":                      "2",
+		"effective_machine_sets.0.name":                 "master",
+		"effective_machine_sets.0.machine_blueprint_id": masterBlueprintID,
+		"effective_machine_sets.0.min_size":             "3",
+		"effective_machine_sets.0.max_size":             "3",
+		"effective_machine_sets.1.name":                 "worker",
+		"effective_machine_sets.1.machine_blueprint_id": workerBlueprintID,
+		"effective_machine_sets.1.min_size":             "1",
+		"effective_machine_sets.1.max_size":             "3",
+
+		"control_plane.#":                      "1",
+		"control_plane.0.count":                "3",
+		"control_plane.0.machine_blueprint_id": masterBlueprintID,
+	} {
+		state.Attributes[k] = v
+	}
+
+	diff, err := r.Diff(context.Background(), state, clusterTestConfig(nil), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := diff.Attributes["control_plane.#"]; !ok {
+		t.Fatal("no diff for the removal of control_plane")
+	}
+
+	for _, key := range []string{"effective_machine_sets.0.min_size", "effective_machine_sets.0.max_size"} {
+		if attrDiff, ok := diff.Attributes[key]; ok && attrDiff.New != "3" {
+			t.Errorf("%s changes from %s to %s, expected the control plane to keep 3 machines", key, attrDiff.Old, attrDiff.New)
+		}
+	}
+}
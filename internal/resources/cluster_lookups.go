@@ -0,0 +1,53 @@
+// (C) Copyright 2023 Hewlett Packard Enterprise Development LP
+
+package resources
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/HewlettPackard/hpegl-containers-go-sdk/pkg/mcaasapi"
+
+	"github.com/HewlettPackard/hpegl-containers-terraform-resources/pkg/client"
+)
+
+// getClusterProvider returns the cluster provider called name on a site
+func getClusterProvider(clientCtx context.Context, c *client.Client, siteID, name string) (*mcaasapi.ClusterProvider, error) {
+	clusterProviders, resp, err := c.CaasClient.ClusterProvidersApi.V1AppliancesIdClusterprovidersGet(clientCtx, siteID, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error getting cluster providers: %w", err)
+	}
+	defer resp.Body.Close()
+
+	for i := range clusterProviders.Items {
+		if clusterProviders.Items[i].Name == name {
+			return &clusterProviders.Items[i], nil
+		}
+	}
+
+	return nil, nil
+}
+
+// getClusterBlueprint returns the cluster blueprint with id on a site
+func getClusterBlueprint(clientCtx context.Context, c *client.Client, siteID, id string) (*mcaasapi.ClusterBlueprint, error) {
+	field := "applianceID eq " + siteID
+	blueprint, resp, err := c.CaasClient.ClusterBlueprintsApi.V1ClusterblueprintsIdGet(clientCtx, id, field)
+	if err != nil {
+		return nil, fmt.Errorf("error getting cluster blueprint %s: %w", id, err)
+	}
+	defer resp.Body.Close()
+
+	return &blueprint, nil
+}
+
+// getMachineBlueprint returns the machine blueprint with id on a site
+func getMachineBlueprint(clientCtx context.Context, c *client.Client, siteID, id string) (*mcaasapi.MachineBlueprint, error) {
+	field := "applianceID eq " + siteID
+	machineBlueprint, resp, err := c.CaasClient.MachineBlueprintsApi.V1MachineblueprintsIdGet(clientCtx, id, field)
+	if err != nil {
+		return nil, fmt.Errorf("error getting machine blueprint %s: %w", id, err)
+	}
+	defer resp.Body.Close()
+
+	return &machineBlueprint, nil
+}
@@ -0,0 +1,163 @@
+// (C) Copyright 2023 Hewlett Packard Enterprise Development LP
+
+package resources
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/HewlettPackard/hpegl-containers-go-sdk/pkg/mcaasapi"
+)
+
+// buildMachineSets returns the machine sets that are sent to V1ClustersIdPut for worker_nodes.  This is
+// the reconciliation applied on every create and update:
+//   - the default machine sets from the blueprint, in blueprint order, are always sent
+//   - a worker_nodes block with the same name as a default machine set overrides it, if the block is
+//     removed the default machine set goes back to the blueprint values
+//   - all other worker_nodes blocks are pools added by the user, they are sent after the defaults in
+//     the order declared, if a block is removed the pool is removed from the cluster
+func buildMachineSets(defaults []mcaasapi.MachineSet, workerNodes []interface{}) []mcaasapi.MachineSet {
+	overrides := make(map[string]mcaasapi.MachineSet)
+	var added []mcaasapi.MachineSet
+
+	isDefault := make(map[string]bool)
+	for _, dms := range defaults {
+		isDefault[dms.Name] = true
+	}
+
+	for _, wn := range workerNodes {
+		ms := getWorkerNodeDetails(wn.(map[string]interface{}))
+		if isDefault[ms.Name] {
+			overrides[ms.Name] = ms
+		} else {
+			added = append(added, ms)
+		}
+	}
+
+	machineSets := make([]mcaasapi.MachineSet, 0, len(defaults)+len(added))
+	for _, dms := range defaults {
+		if ms, ok := overrides[dms.Name]; ok {
+			machineSets = append(machineSets, ms)
+		} else {
+			machineSets = append(machineSets, dms)
+		}
+	}
+
+	return append(machineSets, added...)
+}
+
+// getDefaultMachineSets returns the default machine sets held in default_machine_sets
+func getDefaultMachineSets(defaultMachineSets []interface{}) []mcaasapi.MachineSet {
+	defaults := make([]mcaasapi.MachineSet, 0, len(defaultMachineSets))
+	for _, dms := range defaultMachineSets {
+		defaults = append(defaults, getDefaultMachineSet(dms.(map[string]interface{})))
+	}
+
+	return defaults
+}
+
+// toUpdateClusterMachineSets converts machine sets to the type used by V1ClustersIdPut
+func toUpdateClusterMachineSets(machineSets []mcaasapi.MachineSet) []mcaasapi.UpdateClusterMachineSet {
+	updateMachineSets := make([]mcaasapi.UpdateClusterMachineSet, 0, len(machineSets))
+	for _, ms := range machineSets {
+		updateMachineSets = append(updateMachineSets, mcaasapi.UpdateClusterMachineSet{
+			Name:                 ms.Name,
+			MachineBlueprintId:   ms.MachineBlueprintId,
+			MachineBlueprintName: ms.MachineBlueprintName,
+			Count:                ms.Count,
+			MinSize:              ms.MinSize,
+			MaxSize:              ms.MaxSize,
+		})
+	}
+
+	return updateMachineSets
+}
+
+// flattenEffectiveMachineSets flattens machine sets for effective_machine_sets
+func flattenEffectiveMachineSets(machineSets []mcaasapi.MachineSet) []interface{} {
+	effective := make([]interface{}, 0, len(machineSets))
+	for _, ms := range machineSets {
+		effective = append(effective, flattenWorkerNode(ms))
+	}
+
+	return effective
+}
+
+// writeEffectiveMachineSets sets effective_machine_sets from the live machine sets of the cluster.  They
+// are ordered as buildMachineSets orders them, so that the value matches the one shown in the plan.
+func writeEffectiveMachineSets(d *schema.ResourceData, cluster *mcaasapi.Cluster) error {
+	live := make(map[string]mcaasapi.MachineSet)
+	for _, ms := range cluster.MachineSets {
+		live[ms.Name] = ms
+	}
+
+	defaults := getDefaultMachineSets(d.Get("default_machine_sets").([]interface{}))
+	ordered := make([]mcaasapi.MachineSet, 0, len(cluster.MachineSets))
+	seen := make(map[string]bool)
+
+	for _, ms := range buildMachineSets(defaults, d.Get("worker_nodes").([]interface{})) {
+		if liveMachineSet, ok := live[ms.Name]; ok {
+			ordered = append(ordered, liveMachineSet)
+			seen[ms.Name] = true
+		}
+	}
+
+	for _, ms := range cluster.MachineSets {
+		if !seen[ms.Name] {
+			ordered = append(ordered, ms)
+		}
+	}
+
+	return d.Set("effective_machine_sets", flattenEffectiveMachineSets(ordered))
+}
+
+// customizeDiffWorkerNodes checks that worker_nodes names are unique and that min_size is not more than max_size
+func customizeDiffWorkerNodes(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown("worker_nodes") {
+		return nil
+	}
+
+	names := make(map[string]bool)
+	for _, wn := range d.Get("worker_nodes").([]interface{}) {
+		wnMap, ok := wn.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		name := wnMap["name"].(string)
+		if names[name] {
+			return fmt.Errorf("worker_nodes: more than one block is named %s", name)
+		}
+		names[name] = true
+
+		if wnMap["min_size"].(float64) > wnMap["max_size"].(float64) {
+			return fmt.Errorf("worker_nodes: %s has min_size %v more than max_size %v", name, wnMap["min_size"], wnMap["max_size"])
+		}
+	}
+
+	return nil
+}
+
+// customizeDiffEffectiveMachineSets shows the machine sets that will be sent to V1ClustersIdPut in the plan
+func customizeDiffEffectiveMachineSets(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() != "" && !d.HasChange("worker_nodes") && !d.HasChange("default_machine_sets") && !d.HasChange("control_plane") {
+		return nil
+	}
+
+	defaultMachineSets := d.Get("default_machine_sets").([]interface{})
+
+	// The defaults aren't known until the cluster has been created
+	if len(defaultMachineSets) == 0 || !d.NewValueKnown("worker_nodes") || !d.NewValueKnown("control_plane") {
+		return d.SetNewComputed("effective_machine_sets")
+	}
+
+	machineSets := buildMachineSets(getDefaultMachineSets(defaultMachineSets), d.Get("worker_nodes").([]interface{}))
+	controlPlaneName := getDefaultControlPlaneName(d.Get("default_machine_sets_detail").([]interface{}))
+	priorEffective, _ := d.GetChange("effective_machine_sets")
+	controlPlane := keepControlPlane(d.Get("control_plane").([]interface{}), priorEffective.([]interface{}), controlPlaneName)
+	machineSets = applyControlPlane(machineSets, controlPlaneName, controlPlane)
+
+	return d.SetNew("effective_machine_sets", flattenEffectiveMachineSets(machineSets))
+}
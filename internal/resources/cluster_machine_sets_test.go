@@ -0,0 +1,92 @@
+// (C) Copyright 2023 Hewlett Packard Enterprise Development LP
+
+package resources
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/HewlettPackard/hpegl-containers-go-sdk/pkg/mcaasapi"
+)
+
+func TestBuildMachineSets(t *testing.T) {
+	master := mcaasapi.MachineSet{Name: "master", MachineBlueprintId: "master-bp", MinSize: 1, MaxSize: 1}
+	worker := mcaasapi.MachineSet{Name: "worker", MachineBlueprintId: "worker-bp", MinSize: 1, MaxSize: 3}
+	defaults := []mcaasapi.MachineSet{master, worker}
+
+	testCases := []struct {
+		name        string
+		defaults    []mcaasapi.MachineSet
+		workerNodes []interface{}
+		expected    []mcaasapi.MachineSet
+	}{
+		{
+			name:     "defaults only",
+			defaults: defaults,
+			expected: []mcaasapi.MachineSet{master, worker},
+		},
+		{
+			name:     "default overridden",
+			defaults: defaults,
+			workerNodes: []interface{}{
+				testWorkerNode("worker", "large-bp", 2, 5),
+			},
+			expected: []mcaasapi.MachineSet{
+				master,
+				{Name: "worker", MachineBlueprintId: "large-bp", MinSize: 2, MaxSize: 5},
+			},
+		},
+		{
+			name:     "pools added in declared order after the defaults",
+			defaults: defaults,
+			workerNodes: []interface{}{
+				testWorkerNode("pool-b", "pool-bp", 1, 2),
+				testWorkerNode("worker", "worker-bp", 2, 3),
+				testWorkerNode("pool-a", "pool-bp", 3, 4),
+			},
+			expected: []mcaasapi.MachineSet{
+				master,
+				{Name: "worker", MachineBlueprintId: "worker-bp", MinSize: 2, MaxSize: 3},
+				{Name: "pool-b", MachineBlueprintId: "pool-bp", MinSize: 1, MaxSize: 2},
+				{Name: "pool-a", MachineBlueprintId: "pool-bp", MinSize: 3, MaxSize: 4},
+			},
+		},
+		{
+			// A removed worker_nodes block isn't in workerNodes, the pool is left out and the default it
+			// overrode goes back to the blueprint values
+			name:     "removed pool and override",
+			defaults: defaults,
+			workerNodes: []interface{}{
+				testWorkerNode("pool-a", "pool-bp", 3, 4),
+			},
+			expected: []mcaasapi.MachineSet{
+				master,
+				worker,
+				{Name: "pool-a", MachineBlueprintId: "pool-bp", MinSize: 3, MaxSize: 4},
+			},
+		},
+		{
+			name: "no defaults",
+			workerNodes: []interface{}{
+				testWorkerNode("pool-a", "pool-bp", 3, 4),
+			},
+			expected: []mcaasapi.MachineSet{
+				{Name: "pool-a", MachineBlueprintId: "pool-bp", MinSize: 3, MaxSize: 4},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			got := buildMachineSets(tc.defaults, tc.workerNodes)
+			if !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("got\n%+v\nexpected\n%+v", got, tc.expected)
+			}
+		})
+	}
+
+	if !reflect.DeepEqual(defaults, []mcaasapi.MachineSet{master, worker}) {
+		t.Errorf("the default machine sets were changed: %+v", defaults)
+	}
+}
@@ -0,0 +1,144 @@
+// (C) Copyright 2023 Hewlett Packard Enterprise Development LP
+
+package resources
+
+import (
+	"context"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/HewlettPackard/hpegl-containers-go-sdk/pkg/mcaasapi"
+)
+
+const machineStateReady = "ready"
+
+// clusterProgress logs state transitions, health changes and machine provisioning progress
+// while a cluster operation is being polled.  Only changes are logged at info level, so the
+// CI logs show where a long-running operation is without repeating the same line every poll.
+type clusterProgress struct {
+	operation   string
+	id          string
+	start       time.Time
+	state       string
+	health      string
+	machineSets map[string]machineSetProgress
+}
+
+// machineSetProgress is the number of machines in a machine set, against the number desired
+type machineSetProgress struct {
+	provisioned int
+	ready       int
+	desired     int
+}
+
+func newClusterProgress(operation, id string) *clusterProgress {
+	return &clusterProgress{
+		operation:   operation,
+		id:          id,
+		start:       time.Now(),
+		machineSets: make(map[string]machineSetProgress),
+	}
+}
+
+// update logs the changes between the last polled cluster and cluster
+func (p *clusterProgress) update(ctx context.Context, cluster *mcaasapi.Cluster) {
+	elapsed := p.elapsed()
+
+	if cluster.State != p.state {
+		tflog.Info(ctx, "cluster state transition", map[string]interface{}{
+			"cluster_id": p.id,
+			"operation":  p.operation,
+			"from_state": p.state,
+			"to_state":   cluster.State,
+			"health":     cluster.Health,
+			"elapsed":    elapsed,
+		})
+		p.state = cluster.State
+	}
+
+	if cluster.Health != p.health {
+		tflog.Info(ctx, "cluster health changed", map[string]interface{}{
+			"cluster_id":  p.id,
+			"operation":   p.operation,
+			"from_health": p.health,
+			"to_health":   cluster.Health,
+			"state":       cluster.State,
+			"elapsed":     elapsed,
+		})
+		p.health = cluster.Health
+	}
+
+	for i := range cluster.MachineSetsDetail {
+		msd := &cluster.MachineSetsDetail[i]
+		current := getMachineSetProgress(msd)
+		if previous, ok := p.machineSets[msd.Name]; ok && previous == current {
+			continue
+		}
+
+		tflog.Info(ctx, "machine set progress", map[string]interface{}{
+			"cluster_id":  p.id,
+			"operation":   p.operation,
+			"machine_set": msd.Name,
+			"provisioned": current.provisioned,
+			"ready":       current.ready,
+			"desired":     current.desired,
+			"state":       cluster.State,
+			"elapsed":     elapsed,
+		})
+		p.machineSets[msd.Name] = current
+	}
+
+	tflog.Debug(ctx, "polled cluster", map[string]interface{}{
+		"cluster_id": p.id,
+		"operation":  p.operation,
+		"state":      cluster.State,
+		"health":     cluster.Health,
+		"elapsed":    elapsed,
+	})
+}
+
+// retrying logs a poll that failed and will be retried
+func (p *clusterProgress) retrying(ctx context.Context, reason string, attempt int) {
+	tflog.Warn(ctx, "retrying cluster poll", map[string]interface{}{
+		"cluster_id": p.id,
+		"operation":  p.operation,
+		"reason":     reason,
+		"attempt":    attempt,
+		"elapsed":    p.elapsed(),
+	})
+}
+
+// deleted logs that the cluster is no longer present
+func (p *clusterProgress) deleted(ctx context.Context) {
+	tflog.Info(ctx, "cluster state transition", map[string]interface{}{
+		"cluster_id": p.id,
+		"operation":  p.operation,
+		"from_state": p.state,
+		"to_state":   stateDeleted,
+		"elapsed":    p.elapsed(),
+	})
+	p.state = stateDeleted
+}
+
+func (p *clusterProgress) elapsed() string {
+	return time.Since(p.start).Round(time.Second).String()
+}
+
+func getMachineSetProgress(msd *mcaasapi.MachineSetDetail) machineSetProgress {
+	progress := machineSetProgress{
+		provisioned: len(msd.Machines),
+		desired:     int(msd.Count),
+	}
+	if progress.desired == 0 {
+		progress.desired = int(msd.MinSize)
+	}
+
+	for _, m := range msd.Machines {
+		if m.State == machineStateReady {
+			progress.ready++
+		}
+	}
+
+	return progress
+}
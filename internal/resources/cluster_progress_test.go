@@ -0,0 +1,115 @@
+// (C) Copyright 2023 Hewlett Packard Enterprise Development LP
+
+package resources
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
+
+	"github.com/HewlettPackard/hpegl-containers-go-sdk/pkg/mcaasapi"
+)
+
+func testProgressCluster(state, health string, machineStates ...string) *mcaasapi.Cluster {
+	machines := make([]mcaasapi.Machine, 0, len(machineStates))
+	for _, s := range machineStates {
+		machines = append(machines, mcaasapi.Machine{State: s})
+	}
+
+	return &mcaasapi.Cluster{
+		State:  state,
+		Health: health,
+		MachineSetsDetail: []mcaasapi.MachineSetDetail{
+			{Name: "worker", Count: 2, Machines: machines},
+		},
+	}
+}
+
+func TestClusterProgressUpdate(t *testing.T) {
+	var output bytes.Buffer
+	ctx := tflogtest.RootLogger(context.Background(), &output)
+	progress := newClusterProgress("create", "5d1b7f5e-2b0e-4c6a-9d5f-8f0f3c8e1a27")
+
+	polls := []struct {
+		cluster  *mcaasapi.Cluster
+		messages []string
+	}{
+		{
+			cluster:  testProgressCluster(stateProvisioning, "unknown"),
+			messages: []string{"cluster state transition", "cluster health changed", "machine set progress"},
+		},
+		{
+			// Nothing changed
+			cluster: testProgressCluster(stateProvisioning, "unknown"),
+		},
+		{
+			cluster:  testProgressCluster(stateProvisioning, "unknown", "provisioning"),
+			messages: []string{"machine set progress"},
+		},
+		{
+			cluster:  testProgressCluster(stateProvisioning, "unknown", machineStateReady, "provisioning"),
+			messages: []string{"machine set progress"},
+		},
+		{
+			cluster:  testProgressCluster(stateReady, "ok", machineStateReady, machineStateReady),
+			messages: []string{"cluster state transition", "cluster health changed", "machine set progress"},
+		},
+		{
+			cluster: testProgressCluster(stateReady, "ok", machineStateReady, machineStateReady),
+		},
+	}
+
+	for i, poll := range polls {
+		output.Reset()
+		progress.update(ctx, poll.cluster)
+
+		entries, err := tflogtest.MultilineJSONDecode(&output)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var messages []string
+		for _, entry := range entries {
+			if entry["@level"] == "info" {
+				messages = append(messages, entry["@message"].(string))
+			}
+		}
+
+		if !reflect.DeepEqual(messages, poll.messages) {
+			t.Errorf("poll %d logged %v at info level, expected %v", i, messages, poll.messages)
+		}
+	}
+}
+
+func TestClusterProgressStateTransition(t *testing.T) {
+	var output bytes.Buffer
+	ctx := tflogtest.RootLogger(context.Background(), &output)
+	progress := newClusterProgress("update", "5d1b7f5e-2b0e-4c6a-9d5f-8f0f3c8e1a27")
+
+	progress.update(ctx, testProgressCluster(stateReady, "ok"))
+	output.Reset()
+	progress.update(ctx, testProgressCluster(stateUpdating, "ok"))
+
+	entries, err := tflogtest.MultilineJSONDecode(&output)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, entry := range entries {
+		if entry["@message"] != "cluster state transition" {
+			continue
+		}
+
+		if entry["from_state"] != stateReady || entry["to_state"] != stateUpdating {
+			t.Errorf("logged a transition from %v to %v, expected %s to %s",
+				entry["from_state"], entry["to_state"], stateReady, stateUpdating)
+		}
+
+		return
+	}
+
+	t.Error("the state transition was not logged")
+}
@@ -0,0 +1,119 @@
+// (C) Copyright 2023 Hewlett Packard Enterprise Development LP
+
+package resources
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+
+	"github.com/HewlettPackard/hpegl-containers-terraform-resources/pkg/client"
+)
+
+// Known license statuses, after normalizeLicenseStatus.  Statuses are matched whole rather than by
+// keyword, so that "not expired" isn't taken to be expired.  Any other status is reported as a warning.
+var (
+	licenseInvalidStatuses = []string{
+		"invalid", "expired", "unlicensed", "not-licensed", "revoked", "missing", "inactive", "deactivated",
+		"suspended", "disabled", "terminated", "cancelled", "canceled", "blocked",
+	}
+	licenseWarningStatuses = []string{
+		"expiring", "expiring-soon", "expires-soon", "degraded", "warning", "grace", "grace-period",
+		"in-grace-period",
+	}
+	licenseValidStatuses = []string{"ok", "valid", "active", "licensed"}
+)
+
+// checkClusterProviderLicense looks up the licenses of the cluster provider called clusterProviderName on a
+// site.  It returns an error if a license is invalid and warnings if a license is expiring or degraded.
+func checkClusterProviderLicense(clientCtx context.Context, c *client.Client, siteID, clusterProviderName string) diag.Diagnostics {
+	clusterProvider, err := getClusterProvider(clientCtx, c, siteID, clusterProviderName)
+	if err != nil {
+		return licenseCheckSkipped(err)
+	}
+
+	if clusterProvider == nil || clusterProvider.LicenseInfo == nil {
+		return nil
+	}
+
+	var diags diag.Diagnostics
+	for _, license := range clusterProvider.LicenseInfo.Licenses {
+		severity, ok := licenseSeverity(license.Status)
+		if ok {
+			continue
+		}
+
+		summary := fmt.Sprintf("Cluster provider %s license %s is %s", clusterProviderName, license.Label, license.Status)
+		detail := license.Summary
+		switch {
+		case severity == diag.Error:
+			detail = strings.TrimSpace(detail + "\nClusters cannot be provisioned until the license is renewed, " +
+				"contact your HPE GreenLake administrator.")
+		case !containsString(licenseWarningStatuses, normalizeLicenseStatus(license.Status)):
+			detail = strings.TrimSpace(detail + "\nThe license status is not a known status.")
+		}
+
+		diags = append(diags, diag.Diagnostic{
+			Severity: severity,
+			Summary:  summary,
+			Detail:   detail,
+		})
+	}
+
+	return diags
+}
+
+// licenseCheckSkipped returns the warning shown when the cluster provider can't be looked up.  The license
+// check is only there to fail early, so a lookup failure doesn't stop the create or update.
+func licenseCheckSkipped(err error) diag.Diagnostics {
+	return diag.Diagnostics{{
+		Severity: diag.Warning,
+		Summary:  "The cluster provider license was not checked",
+		Detail:   err.Error(),
+	}}
+}
+
+// licenseSeverity returns the severity of a license status, ok is true if the status is valid
+func licenseSeverity(status string) (diag.Severity, bool) {
+	s := normalizeLicenseStatus(status)
+
+	switch {
+	case containsString(licenseValidStatuses, s):
+		return diag.Warning, true
+	case containsString(licenseInvalidStatuses, s):
+		return diag.Error, false
+	}
+
+	// Warning and unknown statuses are reported, but don't stop the create or update
+	return diag.Warning, false
+}
+
+// licenseStatusValid returns true if a license status is one of the valid statuses
+func licenseStatusValid(status string) bool {
+	_, ok := licenseSeverity(status)
+
+	return ok
+}
+
+// normalizeLicenseStatus lower cases a license status and joins its words with "-", so that
+// "Grace Period", "grace_period" and "grace-period" are the same status
+func normalizeLicenseStatus(status string) string {
+	words := strings.FieldsFunc(strings.ToLower(status), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	return strings.Join(words, "-")
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,89 @@
+// (C) Copyright 2023 Hewlett Packard Enterprise Development LP
+
+package resources
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+)
+
+func TestLicenseSeverity(t *testing.T) {
+	testCases := []struct {
+		status   string
+		severity diag.Severity
+		ok       bool
+	}{
+		{status: "ok", severity: diag.Warning, ok: true},
+		{status: "Valid", severity: diag.Warning, ok: true},
+		{status: "ACTIVE", severity: diag.Warning, ok: true},
+		{status: "licensed", severity: diag.Warning, ok: true},
+
+		{status: "Expiring", severity: diag.Warning},
+		{status: "expires-soon", severity: diag.Warning},
+		{status: "degraded", severity: diag.Warning},
+		{status: "warning", severity: diag.Warning},
+		{status: "grace-period", severity: diag.Warning},
+		{status: "Grace Period", severity: diag.Warning},
+
+		{status: "invalid", severity: diag.Error},
+		{status: "Expired", severity: diag.Error},
+		{status: "unlicensed", severity: diag.Error},
+		{status: "revoked", severity: diag.Error},
+		{status: "missing", severity: diag.Error},
+		{status: "inactive", severity: diag.Error},
+		{status: "Deactivated", severity: diag.Error},
+		{status: "suspended", severity: diag.Error},
+		{status: "disabled", severity: diag.Error},
+		{status: "terminated", severity: diag.Error},
+		{status: "cancelled", severity: diag.Error},
+		{status: "blocked", severity: diag.Error},
+		{status: "Not Licensed", severity: diag.Error},
+
+		// Statuses are matched whole, not by keyword
+		{status: "not expired", severity: diag.Warning},
+		{status: "unblocked", severity: diag.Warning},
+		{status: "cancellation-reverted", severity: diag.Warning},
+		{status: "valid until 2024", severity: diag.Warning},
+		{status: "okay-ish", severity: diag.Warning},
+
+		// Unknown statuses are reported but don't stop the create or update
+		{status: "pending", severity: diag.Warning},
+		{status: "", severity: diag.Warning},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.status, func(t *testing.T) {
+			severity, ok := licenseSeverity(tc.status)
+			if severity != tc.severity || ok != tc.ok {
+				t.Errorf("got severity %v ok %t, expected severity %v ok %t", severity, ok, tc.severity, tc.ok)
+			}
+		})
+	}
+}
+
+func TestNormalizeLicenseStatus(t *testing.T) {
+	for status, expected := range map[string]string{
+		"ok":             "ok",
+		" Grace Period ": "grace-period",
+		"grace_period":   "grace-period",
+		"EXPIRES--SOON":  "expires-soon",
+		"":               "",
+	} {
+		if got := normalizeLicenseStatus(status); got != expected {
+			t.Errorf("normalizeLicenseStatus(%q) returned %q, expected %q", status, got, expected)
+		}
+	}
+}
+
+func TestLicenseCheckSkipped(t *testing.T) {
+	diags := licenseCheckSkipped(errors.New("503 Service Unavailable"))
+	if diags.HasError() {
+		t.Errorf("a failed lookup stops the create: %v", diags)
+	}
+	if len(diags) != 1 || diags[0].Detail != "503 Service Unavailable" {
+		t.Errorf("got %v, expected a single warning with the lookup error", diags)
+	}
+}
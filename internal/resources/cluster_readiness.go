@@ -0,0 +1,314 @@
+// (C) Copyright 2023 Hewlett Packard Enterprise Development LP
+
+package resources
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/HewlettPackard/hpegl-containers-go-sdk/pkg/mcaasapi"
+
+	"github.com/HewlettPackard/hpegl-containers-terraform-resources/pkg/auth"
+	"github.com/HewlettPackard/hpegl-containers-terraform-resources/pkg/client"
+	"github.com/HewlettPackard/hpegl-containers-terraform-resources/pkg/utils"
+)
+
+const (
+	// placeholder states used when waiting for a ready cluster to become healthy
+	stateWaitingForHealth = "waiting-for-health"
+	stateHealthy          = "healthy"
+
+	defaultHealth   = "ok"
+	readyzPath      = "/readyz"
+	readyzTimeout   = 30 * time.Second
+	minHealthWindow = time.Minute
+)
+
+// clusterReadinessRequired returns true if the cluster resource has been asked to wait for more than state ready
+func clusterReadinessRequired(d *schema.ResourceData) bool {
+	return d.Get("wait_for_health").(bool) || d.Get("wait_for_api_server").(bool)
+}
+
+// getAcceptableHealth returns the health values that are accepted when wait_for_health is set
+func getAcceptableHealth(d *schema.ResourceData) []string {
+	var acceptable []string
+	for _, h := range d.Get("acceptable_health").([]interface{}) {
+		acceptable = append(acceptable, h.(string))
+	}
+
+	if len(acceptable) == 0 {
+		acceptable = []string{defaultHealth}
+	}
+
+	return acceptable
+}
+
+// waitForClusterReadiness waits for a cluster that is in state ready to report an acceptable health and,
+// if wait_for_api_server is set, for its kubeconfig to be available and its API server's /readyz to answer
+func waitForClusterReadiness(
+	ctx context.Context,
+	d *schema.ResourceData,
+	c *client.Client,
+	id, spaceID string,
+	timeout time.Duration,
+	meta interface{},
+) error {
+	if !clusterReadinessRequired(d) {
+		return nil
+	}
+
+	// Leave some time to check health even if the state change used up most of the timeout, but never
+	// wait past the deadline of the operation
+	if timeout < minHealthWindow {
+		timeout = minHealthWindow
+	}
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < timeout {
+		timeout = time.Until(deadline)
+	}
+
+	// The API server's /readyz is called with the same client on every poll, so its connections are reused
+	kubeClient := new(kubeHTTPClient)
+	defer kubeClient.close()
+
+	healthStateConf := resource.StateChangeConf{
+		Delay:        0,
+		Pending:      []string{stateWaitingForHealth},
+		Target:       []string{stateHealthy},
+		Timeout:      timeout,
+		MinTimeout:   pollingInterval,
+		PollInterval: c.PollInterval,
+		Refresh:      clusterReadinessRefresh(ctx, d, c, id, spaceID, kubeClient, meta),
+	}
+
+	_, err := healthStateConf.WaitForStateContext(ctx)
+
+	return err
+}
+
+func clusterReadinessRefresh(
+	ctx context.Context,
+	d *schema.ResourceData,
+	c *client.Client,
+	id, spaceID string,
+	kubeClient *kubeHTTPClient,
+	meta interface{},
+) resource.StateRefreshFunc {
+	waitForHealth := d.Get("wait_for_health").(bool)
+	waitForAPIServer := d.Get("wait_for_api_server").(bool)
+	acceptable := getAcceptableHealth(d)
+
+	return func() (interface{}, string, error) {
+		token, err := auth.GetToken(ctx, meta)
+		if err != nil {
+			return nil, "", err
+		}
+		clientCtx := context.WithValue(ctx, mcaasapi.ContextAccessToken, token)
+
+		field := "spaceID eq " + spaceID
+		cluster, resp, err := c.CaasClient.ClustersApi.V1ClustersIdGet(clientCtx, id, field)
+		if err != nil {
+			// Errors such as 401, 403 or 404 won't go away by waiting
+			statusCode := 0
+			if resp != nil {
+				statusCode = resp.StatusCode
+			}
+			if !utils.IsRetryableStatus(statusCode) {
+				return nil, "", fmt.Errorf("error getting cluster %s: %w", id, err)
+			}
+
+			tflog.Warn(ctx, "error getting cluster while waiting for health", map[string]interface{}{
+				"cluster_id": id,
+				"error":      err.Error(),
+			})
+
+			return id, stateWaitingForHealth, nil
+		}
+		defer resp.Body.Close()
+
+		if waitForHealth && !healthAcceptable(cluster.Health, acceptable) {
+			tflog.Info(ctx, "waiting for cluster health", map[string]interface{}{
+				"cluster_id": id,
+				"health":     cluster.Health,
+				"acceptable": acceptable,
+			})
+
+			return id, stateWaitingForHealth, nil
+		}
+
+		if waitForAPIServer {
+			if err = checkAPIServerReady(clientCtx, c, id, kubeClient); err != nil {
+				tflog.Info(ctx, "waiting for cluster API server", map[string]interface{}{
+					"cluster_id": id,
+					"reason":     err.Error(),
+				})
+
+				return id, stateWaitingForHealth, nil
+			}
+		}
+
+		return id, stateHealthy, nil
+	}
+}
+
+func healthAcceptable(health string, acceptable []string) bool {
+	return containsFold(acceptable, health)
+}
+
+// containsFold returns true if values contains s, ignoring case
+func containsFold(values []string, s string) bool {
+	for _, v := range values {
+		if strings.EqualFold(s, v) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// checkAPIServerReady fetches the kubeconfig for the cluster and calls the API server's /readyz endpoint with it
+func checkAPIServerReady(clientCtx context.Context, c *client.Client, id string, kubeClient *kubeHTTPClient) error {
+	kubeconfig, resp, err := c.CaasClient.KubeConfigApi.V1ClustersIdKubeconfigGet(clientCtx, id)
+	if err != nil {
+		return fmt.Errorf("error getting kubeconfig: %w", err)
+	}
+	defer resp.Body.Close()
+
+	kc, _, err := utils.DecodeKubeconfig(kubeconfig.Kubeconfig)
+	if err != nil {
+		return err
+	}
+
+	cluster, err := kc.CurrentCluster()
+	if err != nil {
+		return err
+	}
+
+	user, err := kc.CurrentUser()
+	if err != nil {
+		return err
+	}
+
+	httpClient, err := kubeClient.get(cluster, user)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(clientCtx, readyzTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(cluster.Server, "/")+readyzPath, nil)
+	if err != nil {
+		return err
+	}
+	if user.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+user.Token)
+	}
+
+	readyzResp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer readyzResp.Body.Close()
+
+	if readyzResp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned %s", readyzPath, readyzResp.Status)
+	}
+
+	return nil
+}
+
+// kubeHTTPClient holds the http.Client used to call an API server while waiting for it, the client is
+// only rebuilt if the TLS settings in the kubeconfig change between polls
+type kubeHTTPClient struct {
+	tlsSettings string
+	client      *http.Client
+}
+
+// get returns the http.Client for the TLS settings and client certificate of a kubeconfig
+func (k *kubeHTTPClient) get(cluster *utils.KubeconfigCluster, user *utils.KubeconfigUser) (*http.Client, error) {
+	tlsSettings := strings.Join([]string{
+		fmt.Sprint(cluster.InsecureSkipTLSVerify),
+		cluster.CertificateAuthorityData,
+		user.ClientCertificateData,
+		user.ClientKeyData,
+	}, "\n")
+	if k.client != nil && k.tlsSettings == tlsSettings {
+		return k.client, nil
+	}
+
+	httpClient, err := newKubeHTTPClient(cluster, user)
+	if err != nil {
+		return nil, err
+	}
+
+	k.close()
+	k.tlsSettings, k.client = tlsSettings, httpClient
+
+	return k.client, nil
+}
+
+// close closes the idle connections of the http.Client
+func (k *kubeHTTPClient) close() {
+	if k.client != nil {
+		k.client.CloseIdleConnections()
+	}
+}
+
+// newKubeHTTPClient returns an http.Client set-up with the TLS settings and client certificate of a kubeconfig
+func newKubeHTTPClient(cluster *utils.KubeconfigCluster, user *utils.KubeconfigUser) (*http.Client, error) {
+	// nolint: gosec
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		// Only skip verification if the kubeconfig itself asks for it
+		InsecureSkipVerify: cluster.InsecureSkipTLSVerify,
+	}
+
+	if cluster.CertificateAuthorityData != "" {
+		ca, err := base64.StdEncoding.DecodeString(cluster.CertificateAuthorityData)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding certificate-authority-data: %w", err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(ca) {
+			return nil, errors.New("no certificates found in certificate-authority-data")
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if user.ClientCertificateData != "" && user.ClientKeyData != "" {
+		cert, err := base64.StdEncoding.DecodeString(user.ClientCertificateData)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding client-certificate-data: %w", err)
+		}
+
+		key, err := base64.StdEncoding.DecodeString(user.ClientKeyData)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding client-key-data: %w", err)
+		}
+
+		keyPair, err := tls.X509KeyPair(cert, key)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.Certificates = []tls.Certificate{keyPair}
+	}
+
+	return &http.Client{
+		Transport: &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: tlsConfig,
+		},
+	}, nil
+}
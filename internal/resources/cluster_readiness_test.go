@@ -0,0 +1,114 @@
+// (C) Copyright 2023 Hewlett Packard Enterprise Development LP
+
+package resources
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/HewlettPackard/hpegl-containers-go-sdk/pkg/mcaasapi"
+
+	"github.com/HewlettPackard/hpegl-containers-terraform-resources/pkg/client"
+	"github.com/HewlettPackard/hpegl-containers-terraform-resources/pkg/utils"
+)
+
+func TestKubeHTTPClient(t *testing.T) {
+	kubeClient := new(kubeHTTPClient)
+	defer kubeClient.close()
+
+	cluster := &utils.KubeconfigCluster{Server: "https://caas.example.com:6443"}
+	user := &utils.KubeconfigUser{Token: "token"}
+
+	first, err := kubeClient.get(cluster, user)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A new token doesn't change the TLS settings
+	second, err := kubeClient.get(cluster, &utils.KubeconfigUser{Token: "new-token"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second != first {
+		t.Error("a new client was built for the same TLS settings")
+	}
+
+	third, err := kubeClient.get(&utils.KubeconfigCluster{Server: cluster.Server, InsecureSkipTLSVerify: true}, user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if third == first {
+		t.Error("the client was reused after the TLS settings changed")
+	}
+}
+
+func TestCheckAPIServerReadyReusesConnections(t *testing.T) {
+	const clusterID = "5d1b7f5e-2b0e-4c6a-9d5f-8f0f3c8e1a27"
+
+	var readyzConnections int32
+	readyz := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != readyzPath {
+			http.NotFound(w, r)
+
+			return
+		}
+		fmt.Fprint(w, "ok")
+	}))
+	readyz.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			atomic.AddInt32(&readyzConnections, 1)
+		}
+	}
+	readyz.Start()
+	defer readyz.Close()
+
+	kubeconfig := fmt.Sprintf(`apiVersion: v1
+kind: Config
+clusters:
+- name: caas
+  cluster:
+    server: %s
+contexts:
+- name: caas
+  context:
+    cluster: caas
+    user: caas
+users:
+- name: caas
+  user:
+    token: caas-token
+current-context: caas
+`, readyz.URL)
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"kubeconfig": base64.StdEncoding.EncodeToString([]byte(kubeconfig)),
+		})
+	}))
+	defer api.Close()
+
+	c := &client.Client{CaasClient: mcaasapi.NewAPIClient(&mcaasapi.Configuration{
+		BasePath:      api.URL,
+		DefaultHeader: make(map[string]string),
+	})}
+
+	kubeClient := new(kubeHTTPClient)
+	defer kubeClient.close()
+
+	for i := 0; i < 3; i++ {
+		if err := checkAPIServerReady(context.Background(), c, clusterID, kubeClient); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if n := atomic.LoadInt32(&readyzConnections); n != 1 {
+		t.Errorf("%d connections were opened to the API server, expected the first one to be reused", n)
+	}
+}
@@ -0,0 +1,170 @@
+// (C) Copyright 2023 Hewlett Packard Enterprise Development LP
+
+package resources
+
+import (
+	"context"
+	"testing"
+
+	ctymsgpack "github.com/hashicorp/go-cty/cty/msgpack"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestClusterImmutableArguments(t *testing.T) {
+	r := Cluster()
+
+	testCases := []struct {
+		attribute   string
+		value       string
+		requiresNew bool
+	}{
+		{attribute: "name", value: "renamed", requiresNew: true},
+		{attribute: "blueprint_id", value: otherID, requiresNew: true},
+		{attribute: "site_id", value: otherID, requiresNew: true},
+		{attribute: "space_id", value: otherID, requiresNew: true},
+		{attribute: "kubernetes_version", value: "1.26.0", requiresNew: false},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.attribute, func(t *testing.T) {
+			config := clusterTestConfig(map[string]interface{}{tc.attribute: tc.value})
+
+			diff, err := r.Diff(context.Background(), clusterTestState(), config, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			attrDiff, ok := diff.Attributes[tc.attribute]
+			if !ok {
+				t.Fatalf("no diff for %s", tc.attribute)
+			}
+
+			if attrDiff.RequiresNew != tc.requiresNew {
+				t.Errorf("%s RequiresNew is %t, expected %t", tc.attribute, attrDiff.RequiresNew, tc.requiresNew)
+			}
+
+			if diff.RequiresNew() != tc.requiresNew {
+				t.Errorf("changing %s: diff RequiresNew is %t, expected %t", tc.attribute, diff.RequiresNew(), tc.requiresNew)
+			}
+		})
+	}
+}
+
+func TestClusterNoChanges(t *testing.T) {
+	r := Cluster()
+
+	diff, err := r.Diff(context.Background(), clusterTestState(), clusterTestConfig(nil), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for k, attrDiff := range diff.Attributes {
+		if attrDiff.RequiresNew {
+			t.Errorf("%s requires a new cluster with no configuration changes", k)
+		}
+	}
+}
+
+func TestClusterStateUpgradeV0(t *testing.T) {
+	r := Cluster()
+	server := schema.NewGRPCProviderServer(&schema.Provider{
+		ResourcesMap: map[string]*schema.Resource{"hpegl_caas_cluster": r},
+	})
+
+	testCases := []struct {
+		name     string
+		rawState *tfprotov5.RawState
+	}{
+		{
+			name: "json",
+			rawState: &tfprotov5.RawState{JSON: []byte(`{
+				"id": "` + testClusterID + `",
+				"name": "test",
+				"blueprint_id": "` + testBlueprintID + `",
+				"site_id": "` + testSiteID + `",
+				"space_id": "` + testSpaceID + `",
+				"kubernetes_version": "1.25.0",
+				"ignore_autoscaler_changes": false,
+				"worker_nodes": [{"name": "pool", "machine_blueprint_id": "` + testBlueprintID + `", "min_size": 1, "max_size": 3}]
+			}`)},
+		},
+		{
+			// State written by terraform 0.11 is flatmap, it is decoded with the version 0 schema
+			name: "flatmap",
+			rawState: &tfprotov5.RawState{Flatmap: map[string]string{
+				"id":                                  testClusterID,
+				"name":                                "test",
+				"blueprint_id":                        testBlueprintID,
+				"site_id":                             testSiteID,
+				"space_id":                            testSpaceID,
+				"kubernetes_version":                  "1.25.0",
+				"ignore_autoscaler_changes":           "false",
+				"worker_nodes.#":                      "1",
+				"worker_nodes.0.name":                 "pool",
+				"worker_nodes.0.machine_blueprint_id": testBlueprintID,
+				"worker_nodes.0.min_size":             "1",
+				"worker_nodes.0.max_size":             "3",
+			}},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			resp, err := server.UpgradeResourceState(context.Background(), &tfprotov5.UpgradeResourceStateRequest{
+				TypeName: "hpegl_caas_cluster",
+				Version:  0,
+				RawState: tc.rawState,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, d := range resp.Diagnostics {
+				t.Fatalf("upgrade failed: %s: %s", d.Summary, d.Detail)
+			}
+
+			val, err := ctymsgpack.Unmarshal(resp.UpgradedState.MsgPack, r.CoreConfigSchema().ImpliedType())
+			if err != nil {
+				t.Fatal(err)
+			}
+			state, err := r.ShimInstanceStateFromValue(val)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expected := map[string]string{
+				"name":                    "test",
+				"blueprint_id":            testBlueprintID,
+				"site_id":                 testSiteID,
+				"space_id":                testSpaceID,
+				"worker_nodes.#":          "1",
+				"worker_nodes.0.name":     "pool",
+				"worker_nodes.0.max_size": "3",
+			}
+			for k, v := range expected {
+				if state.Attributes[k] != v {
+					t.Errorf("%s is %q after the upgrade, expected %q", k, state.Attributes[k], v)
+				}
+			}
+
+			// The upgraded state must not replace the cluster when the configuration is unchanged
+			config := clusterTestConfig(map[string]interface{}{
+				"worker_nodes": []interface{}{map[string]interface{}{
+					"name":                 "pool",
+					"machine_blueprint_id": testBlueprintID,
+					"min_size":             1,
+					"max_size":             3,
+				}},
+			})
+			diff, err := r.Diff(context.Background(), state, config, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff.RequiresNew() {
+				t.Errorf("upgraded state replaces the cluster: %v", diff.Attributes)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+// (C) Copyright 2023 Hewlett Packard Enterprise Development LP
+
+package resources
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/HewlettPackard/hpegl-containers-go-sdk/pkg/mcaasapi"
+
+	"github.com/HewlettPackard/hpegl-containers-terraform-resources/pkg/client"
+	"github.com/HewlettPackard/hpegl-containers-terraform-resources/pkg/utils"
+)
+
+// checkClusterUnchanged waits for any operation already in progress on the cluster to finish, and
+// then checks that the cluster's last_update_date is still the one in state.  The update payload
+// is built from state, so sending it after the cluster has been changed by the autoscaler or in
+// the portal would overwrite that change.  The live cluster is returned.
+func checkClusterUnchanged(
+	ctx context.Context,
+	d *schema.ResourceData,
+	c *client.Client,
+	clientCtx context.Context,
+	meta interface{},
+) (*mcaasapi.Cluster, diag.Diagnostics) {
+	id := d.Id()
+	spaceID := d.Get("space_id").(string)
+
+	readyStateConf := resource.StateChangeConf{
+		Delay:        0,
+		Pending:      []string{stateProvisioning, stateCreating, stateRetrying, stateUpdating, stateDeProvisioning, stateUpgrading},
+		Target:       []string{stateReady},
+		Timeout:      d.Timeout("update"),
+		MinTimeout:   pollingInterval,
+		PollInterval: c.PollInterval,
+		Refresh:      clusterRefresh(ctx, d, "pre-update", id, spaceID, stateReady, meta),
+	}
+
+	if _, err := readyStateConf.WaitForStateContext(ctx); err != nil {
+		return nil, diag.Errorf("Error waiting for cluster %s to be ready before update: %s", id, err)
+	}
+
+	field := "spaceID eq " + spaceID
+	cluster, resp, err := c.CaasClient.ClustersApi.V1ClustersIdGet(clientCtx, id, field)
+	if err != nil {
+		// There is no response on a transport or network error
+		if resp == nil {
+			return nil, diag.Errorf("Error in V1ClustersIdGet: %s", err)
+		}
+		errMessage := utils.GetErrorMessage(err, resp.StatusCode)
+
+		return nil, diag.Errorf("Error in V1ClustersIdGet: %s - %s", err, errMessage)
+	}
+	defer resp.Body.Close()
+
+	lastUpdateDate, err := cluster.LastUpdateDate.MarshalText()
+	if err != nil {
+		return nil, diag.FromErr(err)
+	}
+
+	// State written by older versions of the provider may not have last_update_date
+	stateLastUpdateDate := d.Get("last_update_date").(string)
+	if stateLastUpdateDate == "" || stateLastUpdateDate == string(lastUpdateDate) {
+		return &cluster, nil
+	}
+
+	tflog.Warn(ctx, "cluster changed since last refresh", map[string]interface{}{
+		"cluster_id":             id,
+		"last_update_date":       string(lastUpdateDate),
+		"state_last_update_date": stateLastUpdateDate,
+	})
+
+	return nil, diag.Diagnostics{{
+		Severity: diag.Error,
+		Summary:  fmt.Sprintf("Cluster %s has changed since it was last refreshed", cluster.Name),
+		Detail: fmt.Sprintf("The cluster was last updated at %s, but the plan was made against the cluster "+
+			"as it was at %s. It may have been changed by the autoscaler, in the portal, or by another "+
+			"terraform run. No changes have been sent, run terraform plan or apply again to plan against "+
+			"the current cluster.", string(lastUpdateDate), stateLastUpdateDate),
+	}}
+}
@@ -0,0 +1,151 @@
+// (C) Copyright 2023 Hewlett Packard Enterprise Development LP
+
+package resources
+
+import (
+	"context"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/HewlettPackard/hpegl-containers-go-sdk/pkg/mcaasapi"
+
+	"github.com/HewlettPackard/hpegl-containers-terraform-resources/internal/resources/schemas"
+	"github.com/HewlettPackard/hpegl-containers-terraform-resources/pkg/auth"
+	"github.com/HewlettPackard/hpegl-containers-terraform-resources/pkg/client"
+	"github.com/HewlettPackard/hpegl-containers-terraform-resources/pkg/constants"
+)
+
+// clusterTransitionStates are the states a cluster passes through on its way to a stable state
+var clusterTransitionStates = []string{
+	stateInitializing,
+	stateProvisioning,
+	stateDeProvisioning,
+	stateCreating,
+	stateDeleting,
+	stateUpdating,
+	stateUpgrading,
+	stateRetrying,
+}
+
+func ClusterWait() *schema.Resource {
+	return &schema.Resource{
+		Schema:             schemas.ClusterWait(),
+		SchemaVersion:      0,
+		StateUpgraders:     nil,
+		CreateContext:      clusterWaitCreateContext,
+		ReadContext:        clusterWaitReadContext,
+		DeleteContext:      clusterWaitDeleteContext,
+		CustomizeDiff:      customizeDiffProviderDefaults(constants.SpaceID),
+		Importer:           nil,
+		DeprecationMessage: "",
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(clusterAvailableTimeout),
+		},
+		Description: `The cluster wait resource blocks until a CaaS cluster reaches
+			target_state (default "ready"), which must be one of the states reported by
+			the CaaS API.  It is intended for use with clusters that
+			are created with wait_for_completion set to false.  The required inputs are
+			cluster_id and space_id, space_id defaults to the space_id of the caas provider
+			block.  wait_for_health, acceptable_health and
+			wait_for_api_server behave as they do on the cluster resource.  Deleting
+			this resource has no effect on the cluster.`,
+	}
+}
+
+func clusterWaitCreateContext(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c, err := client.GetClientFromMetaMap(meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	id := d.Get("cluster_id").(string)
+	spaceID := d.Get("space_id").(string)
+	targetState := d.Get("target_state").(string)
+	start := time.Now()
+
+	var pending []string
+	for _, s := range clusterTransitionStates {
+		if s != targetState {
+			pending = append(pending, s)
+		}
+	}
+
+	gtf := createGetTokenFunc(ctx, c, id, spaceID, targetState, meta, newClusterProgress("wait", id))
+	waitStateConf := resource.StateChangeConf{
+		Delay:        0,
+		Pending:      pending,
+		Target:       []string{targetState},
+		Timeout:      d.Timeout("create"),
+		MinTimeout:   pollingInterval,
+		PollInterval: c.PollInterval,
+		Refresh: func() (interface{}, string, error) {
+			state, err := gtf()
+
+			return id, state, err
+		},
+	}
+
+	_, err = waitStateConf.WaitForStateContext(ctx)
+	if err != nil {
+		return diag.Errorf("Error waiting for cluster %s to reach state %s: %s", id, targetState, err)
+	}
+
+	if targetState == stateReady {
+		err = waitForClusterReadiness(ctx, d, c, id, spaceID, d.Timeout("create")-time.Since(start), meta)
+		if err != nil {
+			return diag.Errorf("Error waiting for cluster %s to become healthy: %s", id, err)
+		}
+	}
+
+	d.SetId(id)
+
+	if targetState == stateDeleted {
+		return diag.FromErr(d.Set("state", stateDeleted))
+	}
+
+	return clusterWaitReadContext(ctx, d, meta)
+}
+
+// clusterWaitReadContext records the cluster state and health seen once the wait completed, the values
+// are not refreshed afterwards so that later changes to the cluster don't cause the wait to be repeated
+func clusterWaitReadContext(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if d.Get("state").(string) != "" {
+		return nil
+	}
+
+	c, err := client.GetClientFromMetaMap(meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	token, err := auth.GetToken(ctx, meta)
+	if err != nil {
+		return diag.Errorf("Error in getting token: %s", err)
+	}
+	clientCtx := context.WithValue(ctx, mcaasapi.ContextAccessToken, token)
+
+	field := "spaceID eq " + d.Get("space_id").(string)
+	cluster, resp, err := c.CaasClient.ClustersApi.V1ClustersIdGet(clientCtx, d.Id(), field)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer resp.Body.Close()
+
+	if err = d.Set("state", cluster.State); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err = d.Set("health", cluster.Health); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+func clusterWaitDeleteContext(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	d.SetId("")
+
+	return nil
+}
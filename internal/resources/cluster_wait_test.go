@@ -0,0 +1,47 @@
+// (C) Copyright 2023 Hewlett Packard Enterprise Development LP
+
+package resources
+
+import (
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+
+	"github.com/HewlettPackard/hpegl-containers-terraform-resources/internal/resources/schemas"
+)
+
+func TestClusterWaitTargetState(t *testing.T) {
+	validate := schemas.ClusterWait()["target_state"].ValidateDiagFunc
+
+	testCases := []struct {
+		state string
+		valid bool
+	}{
+		{state: stateInitializing, valid: true},
+		{state: stateProvisioning, valid: true},
+		{state: stateDeProvisioning, valid: true},
+		{state: stateCreating, valid: true},
+		{state: stateDeleting, valid: true},
+		{state: stateReady, valid: true},
+		{state: stateDeleted, valid: true},
+		{state: stateUpdating, valid: true},
+		{state: stateUpgrading, valid: true},
+
+		// Placeholder states are never reported by the API, so a wait for them would never finish
+		{state: stateRetrying},
+		{state: stateWaitingForHealth},
+		{state: stateHealthy},
+		{state: "Ready"},
+		{state: ""},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.state, func(t *testing.T) {
+			diags := validate(tc.state, cty.GetAttrPath("target_state"))
+			if diags.HasError() == tc.valid {
+				t.Errorf("target_state %q valid is %t, expected %t: %v", tc.state, !diags.HasError(), tc.valid, diags)
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+// (C) Copyright 2023 Hewlett Packard Enterprise Development LP
+
+package resources
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/HewlettPackard/hpegl-containers-go-sdk/pkg/mcaasapi"
+
+	"github.com/HewlettPackard/hpegl-containers-terraform-resources/internal/resources/schemas"
+	"github.com/HewlettPackard/hpegl-containers-terraform-resources/pkg/client"
+)
+
+// writeDefaultMachineSets sets default_machine_sets and default_machine_sets_detail from the cluster
+// blueprint if they are not already set, this is the case for imported clusters
+func writeDefaultMachineSets(
+	clientCtx context.Context,
+	c *client.Client,
+	d *schema.ResourceData,
+	cluster *mcaasapi.Cluster,
+) error {
+	if len(d.Get("default_machine_sets").([]interface{})) > 0 {
+		return nil
+	}
+
+	field := "applianceID eq " + cluster.ApplianceID
+	blueprint, resp, err := c.CaasClient.ClusterBlueprintsApi.V1ClusterblueprintsIdGet(clientCtx, cluster.ClusterBlueprintId, field)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if err = d.Set("default_machine_sets", schemas.FlattenMachineSets(&blueprint.MachineSets)); err != nil {
+		return err
+	}
+
+	return d.Set("default_machine_sets_detail", schemas.FlattenMachineSetsDetail(&blueprint.MachineSetsDetail))
+}
+
+// writeWorkerNodes rebuilds worker_nodes from the live machine sets of the cluster, so that pools that
+// have been scaled, added or removed outside of terraform show up as drift.  Control plane machine sets
+// are never included, and default machine sets from the blueprint are only included if they are declared
+// in worker_nodes or no longer match the blueprint.  The order of the declared worker_nodes is kept.
+func writeWorkerNodes(d *schema.ResourceData, cluster *mcaasapi.Cluster) error {
+	defaults := make(map[string]mcaasapi.MachineSet)
+	for _, dms := range d.Get("default_machine_sets").([]interface{}) {
+		ms := getDefaultMachineSet(dms.(map[string]interface{}))
+		defaults[ms.Name] = ms
+	}
+
+	live := make(map[string]mcaasapi.MachineSet)
+	for _, ms := range cluster.MachineSets {
+		live[ms.Name] = ms
+	}
+
+	controlPlane := make(map[string]bool)
+	for i := range cluster.MachineSetsDetail {
+		msd := &cluster.MachineSetsDetail[i]
+		if isControlPlaneMachineSet(msd) {
+			controlPlane[msd.Name] = true
+		}
+	}
+
+	var workerNodes []interface{}
+	declared := make(map[string]bool)
+
+	for _, wn := range d.Get("worker_nodes").([]interface{}) {
+		prior := getWorkerNodeDetails(wn.(map[string]interface{}))
+		declared[prior.Name] = true
+
+		ms, ok := live[prior.Name]
+		if !ok {
+			// The pool has been removed
+			continue
+		}
+
+		workerNodes = append(workerNodes, flattenWorkerNode(ms))
+	}
+
+	for _, ms := range cluster.MachineSets {
+		if declared[ms.Name] || controlPlane[ms.Name] {
+			continue
+		}
+
+		if dms, ok := defaults[ms.Name]; ok && machineSetsEqual(dms, ms) {
+			continue
+		}
+
+		workerNodes = append(workerNodes, flattenWorkerNode(ms))
+	}
+
+	return d.Set("worker_nodes", workerNodes)
+}
+
+// keepAutoscaledCounts sets the count of each autoscaled machine set, one with min_size less than max_size,
+// to the number of machines it has in the live cluster, kept within its min_size and max_size.  Without this
+// an update would resize pools that the autoscaler has scaled.
+func keepAutoscaledCounts(machineSets []mcaasapi.MachineSet, live *mcaasapi.Cluster) []mcaasapi.MachineSet {
+	counts := make(map[string]int32)
+	for _, msd := range live.MachineSetsDetail {
+		counts[msd.Name] = msd.Count
+	}
+
+	for i := range machineSets {
+		ms := &machineSets[i]
+		count, ok := counts[ms.Name]
+		if !ok || ms.MinSize >= ms.MaxSize {
+			continue
+		}
+
+		switch {
+		case count < ms.MinSize:
+			count = ms.MinSize
+		case count > ms.MaxSize:
+			count = ms.MaxSize
+		}
+		ms.Count = count
+	}
+
+	return machineSets
+}
+
+// isControlPlaneMachineSet returns true for machine sets that don't have the worker role
+func isControlPlaneMachineSet(msd *mcaasapi.MachineSetDetail) bool {
+	for _, role := range msd.MachineRoles {
+		if role == mcaasapi.WORKER_MachineRolesType {
+			return false
+		}
+	}
+
+	return len(msd.MachineRoles) > 0
+}
+
+func machineSetsEqual(a, b mcaasapi.MachineSet) bool {
+	return a.MachineBlueprintId == b.MachineBlueprintId && a.MinSize == b.MinSize && a.MaxSize == b.MaxSize
+}
+
+func flattenWorkerNode(ms mcaasapi.MachineSet) map[string]interface{} {
+	return map[string]interface{}{
+		"name":                 ms.Name,
+		"machine_blueprint_id": ms.MachineBlueprintId,
+		"min_size":             float64(ms.MinSize),
+		"max_size":             float64(ms.MaxSize),
+	}
+}
@@ -0,0 +1,192 @@
+// (C) Copyright 2023 Hewlett Packard Enterprise Development LP
+
+package resources
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/HewlettPackard/hpegl-containers-go-sdk/pkg/mcaasapi"
+
+	"github.com/HewlettPackard/hpegl-containers-terraform-resources/internal/resources/schemas"
+)
+
+func testMachineSetDetail(name string, count int32, roles ...mcaasapi.MachineRolesType) mcaasapi.MachineSetDetail {
+	return mcaasapi.MachineSetDetail{Name: name, Count: count, MachineRoles: roles}
+}
+
+// testClusterData returns the ResourceData of a cluster with workerNodes declared and a blueprint that has
+// a master and a worker default machine set
+func testClusterData(t *testing.T, ignoreAutoscaler bool, workerNodes ...map[string]interface{}) *schema.ResourceData {
+	t.Helper()
+
+	raw := map[string]interface{}{
+		"name":                      "test",
+		"blueprint_id":              "3f31daa9-9777-4c06-a4d0-e49215f5e48c",
+		"site_id":                   "233eead2-20de-47ab-b266-2413cdaa3685",
+		"space_id":                  "f866c9bd-2d2c-4e60-aab0-64737df96273",
+		"ignore_autoscaler_changes": ignoreAutoscaler,
+	}
+	var wns []interface{}
+	for _, wn := range workerNodes {
+		wns = append(wns, wn)
+	}
+	raw["worker_nodes"] = wns
+
+	d := schema.TestResourceDataRaw(t, schemas.Cluster(), raw)
+	err := d.Set("default_machine_sets", []interface{}{
+		flattenWorkerNode(mcaasapi.MachineSet{Name: "master", MachineBlueprintId: "master-bp", MinSize: 1, MaxSize: 1}),
+		flattenWorkerNode(mcaasapi.MachineSet{Name: "worker", MachineBlueprintId: "worker-bp", MinSize: 1, MaxSize: 3}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return d
+}
+
+func testWorkerNode(name, blueprintID string, minSize, maxSize int32) map[string]interface{} {
+	return flattenWorkerNode(mcaasapi.MachineSet{Name: name, MachineBlueprintId: blueprintID, MinSize: minSize, MaxSize: maxSize})
+}
+
+func TestWriteWorkerNodes(t *testing.T) {
+	cluster := &mcaasapi.Cluster{
+		MachineSets: []mcaasapi.MachineSet{
+			{Name: "master", MachineBlueprintId: "master-bp", MinSize: 3, MaxSize: 3},
+			{Name: "worker", MachineBlueprintId: "worker-bp", MinSize: 1, MaxSize: 3},
+			{Name: "pool-b", MachineBlueprintId: "pool-bp", MinSize: 2, MaxSize: 6},
+			{Name: "pool-a", MachineBlueprintId: "pool-bp", MinSize: 1, MaxSize: 4},
+			{Name: "portal", MachineBlueprintId: "pool-bp", MinSize: 1, MaxSize: 1},
+		},
+		MachineSetsDetail: []mcaasapi.MachineSetDetail{
+			testMachineSetDetail("master", 3, mcaasapi.CONTROLPLANE_MachineRolesType, mcaasapi.ETCD_MachineRolesType),
+			testMachineSetDetail("worker", 2, mcaasapi.WORKER_MachineRolesType),
+			testMachineSetDetail("pool-b", 3, mcaasapi.WORKER_MachineRolesType),
+			testMachineSetDetail("pool-a", 3, mcaasapi.WORKER_MachineRolesType),
+			testMachineSetDetail("portal", 1, mcaasapi.WORKER_MachineRolesType),
+		},
+	}
+
+	testCases := []struct {
+		name             string
+		ignoreAutoscaler bool
+		declared         []map[string]interface{}
+		expected         []interface{}
+	}{
+		{
+			name: "declared order is kept and undeclared pools are added",
+			declared: []map[string]interface{}{
+				testWorkerNode("pool-a", "pool-bp", 1, 4),
+				testWorkerNode("pool-b", "pool-bp", 2, 6),
+			},
+			expected: []interface{}{
+				testWorkerNode("pool-a", "pool-bp", 1, 4),
+				testWorkerNode("pool-b", "pool-bp", 2, 6),
+				testWorkerNode("portal", "pool-bp", 1, 1),
+			},
+		},
+		{
+			name: "removed pool",
+			declared: []map[string]interface{}{
+				testWorkerNode("pool-a", "pool-bp", 1, 4),
+				testWorkerNode("pool-b", "pool-bp", 2, 6),
+				testWorkerNode("gone", "pool-bp", 1, 2),
+			},
+			expected: []interface{}{
+				testWorkerNode("pool-a", "pool-bp", 1, 4),
+				testWorkerNode("pool-b", "pool-bp", 2, 6),
+				testWorkerNode("portal", "pool-bp", 1, 1),
+			},
+		},
+		{
+			name:             "bounds changed out-of-band are drift even with ignore_autoscaler_changes",
+			ignoreAutoscaler: true,
+			declared: []map[string]interface{}{
+				testWorkerNode("pool-a", "pool-bp", 1, 5),
+				testWorkerNode("pool-b", "pool-bp", 1, 6),
+				testWorkerNode("portal", "pool-bp", 1, 1),
+			},
+			expected: []interface{}{
+				testWorkerNode("pool-a", "pool-bp", 1, 4),
+				testWorkerNode("pool-b", "pool-bp", 2, 6),
+				testWorkerNode("portal", "pool-bp", 1, 1),
+			},
+		},
+		{
+			name: "declared default machine set",
+			declared: []map[string]interface{}{
+				testWorkerNode("worker", "worker-bp", 2, 3),
+			},
+			expected: []interface{}{
+				testWorkerNode("worker", "worker-bp", 1, 3),
+				testWorkerNode("pool-b", "pool-bp", 2, 6),
+				testWorkerNode("pool-a", "pool-bp", 1, 4),
+				testWorkerNode("portal", "pool-bp", 1, 1),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			d := testClusterData(t, tc.ignoreAutoscaler, tc.declared...)
+
+			if err := writeWorkerNodes(d, cluster); err != nil {
+				t.Fatal(err)
+			}
+
+			if got := d.Get("worker_nodes").([]interface{}); !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("got worker_nodes\n%v\nexpected\n%v", got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestWriteWorkerNodesChangedDefault(t *testing.T) {
+	cluster := &mcaasapi.Cluster{
+		MachineSets: []mcaasapi.MachineSet{
+			{Name: "worker", MachineBlueprintId: "worker-bp", MinSize: 2, MaxSize: 5},
+		},
+		MachineSetsDetail: []mcaasapi.MachineSetDetail{
+			testMachineSetDetail("worker", 2, mcaasapi.WORKER_MachineRolesType),
+		},
+	}
+
+	d := testClusterData(t, false)
+	if err := writeWorkerNodes(d, cluster); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []interface{}{testWorkerNode("worker", "worker-bp", 2, 5)}
+	if got := d.Get("worker_nodes").([]interface{}); !reflect.DeepEqual(got, expected) {
+		t.Errorf("got worker_nodes %v, expected the changed default machine set %v", got, expected)
+	}
+}
+
+func TestKeepAutoscaledCounts(t *testing.T) {
+	live := &mcaasapi.Cluster{
+		MachineSetsDetail: []mcaasapi.MachineSetDetail{
+			testMachineSetDetail("within", 3),
+			testMachineSetDetail("below", 1),
+			testMachineSetDetail("above", 9),
+			testMachineSetDetail("fixed", 2),
+		},
+	}
+
+	machineSets := keepAutoscaledCounts([]mcaasapi.MachineSet{
+		{Name: "within", MinSize: 1, MaxSize: 5},
+		{Name: "below", MinSize: 2, MaxSize: 5},
+		{Name: "above", MinSize: 2, MaxSize: 5},
+		{Name: "fixed", MinSize: 3, MaxSize: 3},
+		{Name: "new", MinSize: 1, MaxSize: 5},
+	}, live)
+
+	expected := map[string]int32{"within": 3, "below": 2, "above": 5, "fixed": 0, "new": 0}
+	for _, ms := range machineSets {
+		if ms.Count != expected[ms.Name] {
+			t.Errorf("%s has count %d, expected %d", ms.Name, ms.Count, expected[ms.Name])
+		}
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/HewlettPackard/hpegl-containers-terraform-resources/internal/resources/schemas"
 	"github.com/HewlettPackard/hpegl-containers-terraform-resources/pkg/auth"
 	"github.com/HewlettPackard/hpegl-containers-terraform-resources/pkg/client"
+	"github.com/HewlettPackard/hpegl-containers-terraform-resources/pkg/constants"
 
 	"github.com/HewlettPackard/hpegl-containers-go-sdk/pkg/mcaasapi"
 )
@@ -26,7 +27,8 @@ func DataSourceAppliance() *schema.Resource {
 		DeprecationMessage: "",
 		Timeouts:           nil,
 		Description: `Appliance data source allows reading appliance data 
-			based on name and space ID. Required inputs are name and space_id`,
+			based on name and space ID. Required inputs are name and space_id,
+			space_id defaults to the space_id of the caas provider block`,
 	}
 }
 
@@ -44,7 +46,10 @@ func dataSourceApplianceReadContext(ctx context.Context, d *schema.ResourceData,
 
 	var diags diag.Diagnostics
 
-	spaceID := d.Get("space_id").(string)
+	spaceID, err := setProviderDefault(d, c, constants.SpaceID)
+	if err != nil {
+		return diag.FromErr(err)
+	}
 	field := "spaceID eq " + spaceID
 	appliances, resp, err := c.CaasClient.SitesApi.V1AppliancesGet(clientCtx, field)
 	if err != nil {
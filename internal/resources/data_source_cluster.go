@@ -12,6 +12,7 @@ import (
 	"github.com/HewlettPackard/hpegl-containers-terraform-resources/internal/resources/schemas"
 	"github.com/HewlettPackard/hpegl-containers-terraform-resources/pkg/auth"
 	"github.com/HewlettPackard/hpegl-containers-terraform-resources/pkg/client"
+	"github.com/HewlettPackard/hpegl-containers-terraform-resources/pkg/constants"
 )
 
 func DataSourceCluster() *schema.Resource {
@@ -25,7 +26,15 @@ func DataSourceCluster() *schema.Resource {
 		DeprecationMessage: "",
 		Timeouts:           nil,
 		Description: `Cluster data source allows reading cluster data 
-			based on name and space ID. Required inputs are name and space_id`,
+			based on name and space ID. Required inputs are name and space_id,
+			space_id defaults to the space_id of the caas provider block.
+			The kubeconfig is available base64-encoded as kubeconfig, as YAML as
+			kubeconfig_raw, and decoded into kube_host, cluster_ca_certificate,
+			kube_token, client_certificate and client_key, all are sensitive.
+			If pgp_key is set to keybase:<username> or a PGP public key (armored or
+			base64) the kubeconfig YAML is only available as encrypted_kubeconfig,
+			base64-encoded and encrypted to that key, with its fingerprint in
+			key_fingerprint`,
 	}
 }
 
@@ -43,7 +52,10 @@ func dataSourceClusterReadContext(ctx context.Context, d *schema.ResourceData, m
 
 	var diags diag.Diagnostics
 
-	spaceID := d.Get("space_id").(string)
+	spaceID, err := setProviderDefault(d, c, constants.SpaceID)
+	if err != nil {
+		return diag.FromErr(err)
+	}
 	field := "spaceID eq " + spaceID
 	clusters, resp, err := c.CaasClient.ClustersApi.V1ClustersGet(clientCtx, field)
 	if err != nil {
@@ -73,7 +85,7 @@ func dataSourceClusterReadContext(ctx context.Context, d *schema.ResourceData, m
 		return diag.FromErr(err)
 	}
 
-	if err = d.Set("kubeconfig", kubeconfig.Kubeconfig); err != nil {
+	if err = writeClusterKubeconfig(ctx, d, kubeconfig.Kubeconfig); err != nil {
 		return diag.FromErr(err)
 	}
 
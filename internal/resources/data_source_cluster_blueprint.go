@@ -11,6 +11,7 @@ import (
 	"github.com/HewlettPackard/hpegl-containers-terraform-resources/internal/resources/schemas"
 	"github.com/HewlettPackard/hpegl-containers-terraform-resources/pkg/auth"
 	"github.com/HewlettPackard/hpegl-containers-terraform-resources/pkg/client"
+	"github.com/HewlettPackard/hpegl-containers-terraform-resources/pkg/constants"
 
 	"github.com/HewlettPackard/hpegl-containers-go-sdk/pkg/mcaasapi"
 )
@@ -26,7 +27,8 @@ func DataSourceClusterBlueprint() *schema.Resource {
 		DeprecationMessage: "",
 		Timeouts:           nil,
 		Description: `Cluster Blueprint data source allows reading cluster blueprint data 
-			based on blueprint name and space ID. Required inputs are name and space_id`,
+			based on blueprint name and site ID. Required inputs are name and site_id,
+			site_id defaults to the site_id of the caas provider block`,
 	}
 }
 
@@ -44,7 +46,10 @@ func dataSourceClusterBlueprintReadContext(ctx context.Context, d *schema.Resour
 
 	var diags diag.Diagnostics
 
-	siteID := d.Get("site_id").(string)
+	siteID, err := setProviderDefault(d, c, constants.SiteID)
+	if err != nil {
+		return diag.FromErr(err)
+	}
 	field := "applianceID eq " + siteID
 	blueprints, resp, err := c.CaasClient.ClusterBlueprintsApi.V1ClusterblueprintsGet(clientCtx, field)
 	if err != nil {
@@ -0,0 +1,122 @@
+// (C) Copyright 2023 Hewlett Packard Enterprise Development LP
+
+package resources
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/HewlettPackard/hpegl-containers-go-sdk/pkg/mcaasapi"
+
+	"github.com/HewlettPackard/hpegl-containers-terraform-resources/internal/resources/schemas"
+	"github.com/HewlettPackard/hpegl-containers-terraform-resources/pkg/auth"
+	"github.com/HewlettPackard/hpegl-containers-terraform-resources/pkg/client"
+	"github.com/HewlettPackard/hpegl-containers-terraform-resources/pkg/constants"
+	"github.com/HewlettPackard/hpegl-containers-terraform-resources/pkg/utils"
+)
+
+func DataSourceClusterMachines() *schema.Resource {
+	return &schema.Resource{
+		Schema:             schemas.ClusterMachines(),
+		ReadContext:        dataSourceClusterMachinesReadContext,
+		SchemaVersion:      0,
+		StateUpgraders:     nil,
+		CustomizeDiff:      nil,
+		Importer:           nil,
+		DeprecationMessage: "",
+		Timeouts:           nil,
+		Description: `ClusterMachines data source lists every machine of a cluster in machines,
+			with the name, roles and size of the machine set it belongs to.  The required
+			inputs are cluster_id and space_id, space_id defaults to the space_id of the caas
+			provider block.  Set roles to only list machines with one of
+			those roles (controlplane, etcd or worker), and health to only list machines
+			with one of those health values.`,
+	}
+}
+
+func dataSourceClusterMachinesReadContext(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c, err := client.GetClientFromMetaMap(meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	token, err := auth.GetToken(ctx, meta)
+	if err != nil {
+		return diag.Errorf("Error in getting token: %s", err)
+	}
+	clientCtx := context.WithValue(ctx, mcaasapi.ContextAccessToken, token)
+
+	id := d.Get("cluster_id").(string)
+	spaceID, err := setProviderDefault(d, c, constants.SpaceID)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	field := "spaceID eq " + spaceID
+	cluster, resp, err := c.CaasClient.ClustersApi.V1ClustersIdGet(clientCtx, id, field)
+	if err != nil {
+		// There is no response on a transport or network error
+		if resp == nil {
+			return diag.Errorf("Error in V1ClustersIdGet: %s", err)
+		}
+		errMessage := utils.GetErrorMessage(err, resp.StatusCode)
+
+		return diag.Errorf("Error in V1ClustersIdGet: %s - %s", err, errMessage)
+	}
+	defer resp.Body.Close()
+
+	d.SetId(cluster.Id)
+
+	roles := getStringList(d, "roles")
+	health := getStringList(d, "health")
+
+	if err = d.Set("machines", flattenClusterMachines(cluster.MachineSetsDetail, roles, health)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+// flattenClusterMachines returns the machines of all machine sets that have one of roles, if set, and
+// one of health, if set
+func flattenClusterMachines(machineSetsDetail []mcaasapi.MachineSetDetail, roles, health []string) []interface{} {
+	machines := make([]interface{}, 0)
+
+	for i := range machineSetsDetail {
+		msd := &machineSetsDetail[i]
+
+		machineRoles := make([]string, 0, len(msd.MachineRoles))
+		for _, role := range msd.MachineRoles {
+			machineRoles = append(machineRoles, string(role))
+		}
+
+		if len(roles) > 0 && !hasAnyRole(machineRoles, roles) {
+			continue
+		}
+
+		for j, machine := range schemas.FlattenMachines(&msd.Machines) {
+			if len(health) > 0 && !containsFold(health, msd.Machines[j].Health) {
+				continue
+			}
+
+			m := machine.(map[string]interface{})
+			m["machine_set"] = msd.Name
+			m["machine_roles"] = machineRoles
+			m["size"] = msd.Size
+			machines = append(machines, m)
+		}
+	}
+
+	return machines
+}
+
+func hasAnyRole(machineRoles, roles []string) bool {
+	for _, role := range roles {
+		if containsFold(machineRoles, role) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,26 @@
+// (C) Copyright 2023 Hewlett Packard Enterprise Development LP
+
+package resources
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/HewlettPackard/hpegl-containers-terraform-resources/internal/resources/schemas"
+)
+
+func TestDataSourceClusterMachinesNetworkError(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, schemas.ClusterMachines(), map[string]interface{}{"cluster_id": "5d1b7f5e-2b0e-4c6a-9d5f-8f0f3c8e1a27"})
+
+	// A closed server fails every request with a network error
+	server := httptest.NewServer(nil)
+	server.Close()
+
+	diags := dataSourceClusterMachinesReadContext(context.Background(), d, testMeta(server.URL))
+	if !diags.HasError() {
+		t.Fatal("expected an error reading from an unreachable API")
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/HewlettPackard/hpegl-containers-terraform-resources/internal/resources/schemas"
 	"github.com/HewlettPackard/hpegl-containers-terraform-resources/pkg/auth"
 	"github.com/HewlettPackard/hpegl-containers-terraform-resources/pkg/client"
+	"github.com/HewlettPackard/hpegl-containers-terraform-resources/pkg/constants"
 
 	"github.com/HewlettPackard/hpegl-containers-go-sdk/pkg/mcaasapi"
 )
@@ -25,7 +26,8 @@ func DataSourceClusterProvider() *schema.Resource {
 		DeprecationMessage: "",
 		Timeouts:           nil,
 		Description: `ClusterProvider data source allows reading Cluster Provider data 
-			based on name and site ID. Required inputs are name and site ID`,
+			based on name and site ID. Required inputs are name and site_id,
+			site_id defaults to the site_id of the caas provider block`,
 	}
 }
 
@@ -43,7 +45,10 @@ func dataSourceClusterProviderReadContext(ctx context.Context, d *schema.Resourc
 
 	var diags diag.Diagnostics
 
-	applianceID := d.Get("site_id").(string)
+	applianceID, err := setProviderDefault(d, c, constants.SiteID)
+	if err != nil {
+		return diag.FromErr(err)
+	}
 
 	clusterProviders, resp, err := c.CaasClient.ClusterProvidersApi.V1AppliancesIdClusterprovidersGet(clientCtx, applianceID, nil)
 	if err != nil {
@@ -0,0 +1,299 @@
+// (C) Copyright 2023 Hewlett Packard Enterprise Development LP
+
+package resources
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/HewlettPackard/hpegl-containers-go-sdk/pkg/mcaasapi"
+
+	"github.com/HewlettPackard/hpegl-containers-terraform-resources/internal/resources/schemas"
+	"github.com/HewlettPackard/hpegl-containers-terraform-resources/pkg/auth"
+	"github.com/HewlettPackard/hpegl-containers-terraform-resources/pkg/client"
+	"github.com/HewlettPackard/hpegl-containers-terraform-resources/pkg/constants"
+)
+
+// clusterProviderCriteria are the requirements a cluster provider and its storage class must meet
+type clusterProviderCriteria struct {
+	kubernetesVersion       string
+	acceptableHealth        []string
+	acceptableStates        []string
+	acceptableLicenseStatus []string
+	accessProtocol          string
+	encryption              string
+	dedupe                  string
+	minIOPS                 float64
+}
+
+// clusterProviderMatch is a cluster provider that meets the criteria, with the best storage class
+type clusterProviderMatch struct {
+	clusterProvider *mcaasapi.ClusterProvider
+	storageClass    *mcaasapi.StorageClass
+}
+
+func DataSourceClusterProviderSelection() *schema.Resource {
+	return &schema.Resource{
+		Schema:             schemas.ClusterProviderSelection(),
+		ReadContext:        dataSourceClusterProviderSelectionReadContext,
+		SchemaVersion:      0,
+		StateUpgraders:     nil,
+		CustomizeDiff:      nil,
+		Importer:           nil,
+		DeprecationMessage: "",
+		Timeouts:           nil,
+		Description: `ClusterProviderSelection data source selects the best cluster provider and
+			storage class on a site, so that configuration doesn't depend on their names.
+			The required input is site_id, which defaults to the site_id of the caas provider
+			block.  Cluster providers are only considered if their
+			health is one of acceptable_health (default ["ok"]), their state is one of
+			acceptable_states (any state by default), all of their licenses have a status
+			in acceptable_license_status (by default any status that the license check on
+			cluster create accepts as valid: ok, valid, active or licensed), they support kubernetes_version
+			if it is set, and they have a storage class that matches access_protocol,
+			encryption and dedupe, where set, with at least min_iops.  The cluster providers
+			that are left are ranked by the cpu, then nodes, then clusters of their
+			available_capacity, and the names in that order are in ranking.  name,
+			cluster_provider_id, kubernetes_versions and available_capacity are those of
+			the best one, and storage_class is its cheapest matching storage class.  The
+			read fails, with the reason each cluster provider was rejected, if none match.`,
+	}
+}
+
+func dataSourceClusterProviderSelectionReadContext(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c, err := client.GetClientFromMetaMap(meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	token, err := auth.GetToken(ctx, meta)
+	if err != nil {
+		return diag.Errorf("Error in getting token: %s", err)
+	}
+	clientCtx := context.WithValue(ctx, mcaasapi.ContextAccessToken, token)
+
+	siteID, err := setProviderDefault(d, c, constants.SiteID)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	clusterProviders, resp, err := c.CaasClient.ClusterProvidersApi.V1AppliancesIdClusterprovidersGet(clientCtx, siteID, nil)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer resp.Body.Close()
+
+	criteria := getClusterProviderCriteria(d)
+
+	var matches []clusterProviderMatch
+	var rejected []string
+	for i := range clusterProviders.Items {
+		clusterProvider := &clusterProviders.Items[i]
+		storageClass, reason := matchClusterProvider(clusterProvider, criteria)
+		if reason != "" {
+			rejected = append(rejected, fmt.Sprintf("%s: %s", clusterProvider.Name, reason))
+
+			continue
+		}
+
+		matches = append(matches, clusterProviderMatch{clusterProvider: clusterProvider, storageClass: storageClass})
+	}
+
+	if len(matches) == 0 {
+		return diag.Diagnostics{{
+			Severity: diag.Error,
+			Summary:  fmt.Sprintf("No cluster provider on site '%s' matches the selection criteria", siteID),
+			Detail:   strings.Join(rejected, "\n"),
+		}}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return clusterProviderRanksHigher(matches[i].clusterProvider, matches[j].clusterProvider)
+	})
+
+	best := matches[0]
+	d.SetId(best.clusterProvider.Id)
+
+	if err = writeClusterProviderSelectionValues(d, matches); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+func getClusterProviderCriteria(d *schema.ResourceData) clusterProviderCriteria {
+	criteria := clusterProviderCriteria{
+		kubernetesVersion:       d.Get("kubernetes_version").(string),
+		acceptableHealth:        getStringList(d, "acceptable_health"),
+		acceptableStates:        getStringList(d, "acceptable_states"),
+		acceptableLicenseStatus: getStringList(d, "acceptable_license_status"),
+		accessProtocol:          d.Get("access_protocol").(string),
+		encryption:              d.Get("encryption").(string),
+		dedupe:                  d.Get("dedupe").(string),
+		minIOPS:                 float64(d.Get("min_iops").(int)),
+	}
+
+	if len(criteria.acceptableHealth) == 0 {
+		criteria.acceptableHealth = []string{defaultHealth}
+	}
+
+	return criteria
+}
+
+func getStringList(d *schema.ResourceData, key string) []string {
+	var values []string
+	for _, v := range d.Get(key).([]interface{}) {
+		values = append(values, v.(string))
+	}
+
+	return values
+}
+
+// matchClusterProvider returns the cheapest storage class of clusterProvider that matches criteria,
+// or the reason clusterProvider doesn't match
+// nolint: cyclop
+func matchClusterProvider(clusterProvider *mcaasapi.ClusterProvider, criteria clusterProviderCriteria) (*mcaasapi.StorageClass, string) {
+	if !healthAcceptable(clusterProvider.Health, criteria.acceptableHealth) {
+		return nil, fmt.Sprintf("health is %s", clusterProvider.Health)
+	}
+
+	if len(criteria.acceptableStates) > 0 && !containsFold(criteria.acceptableStates, clusterProvider.State) {
+		return nil, fmt.Sprintf("state is %s", clusterProvider.State)
+	}
+
+	if clusterProvider.LicenseInfo != nil {
+		for _, license := range clusterProvider.LicenseInfo.Licenses {
+			if !licenseAcceptable(license.Status, criteria.acceptableLicenseStatus) {
+				return nil, fmt.Sprintf("license %s status is %s", license.Label, license.Status)
+			}
+		}
+	}
+
+	if criteria.kubernetesVersion != "" && !supportsKubernetesVersion(clusterProvider, criteria.kubernetesVersion) {
+		return nil, fmt.Sprintf("kubernetes version %s is not supported", criteria.kubernetesVersion)
+	}
+
+	var best *mcaasapi.StorageClass
+	var bestCost float64
+	for i := range clusterProvider.StorageClasses {
+		sc := &clusterProvider.StorageClasses[i]
+		if !storageClassMatches(sc, criteria) {
+			continue
+		}
+
+		// Storage classes without a cost are only picked if there's nothing else
+		cost, err := parseNumber(sc.CostPerGB)
+		if err != nil {
+			cost = -1
+		}
+
+		if best == nil || (cost >= 0 && (bestCost < 0 || cost < bestCost)) {
+			best = sc
+			bestCost = cost
+		}
+	}
+
+	if best == nil {
+		return nil, "no storage class matches"
+	}
+
+	return best, ""
+}
+
+// licenseAcceptable returns true if a license status is one of acceptable, or if acceptable is empty
+// and the status is one that the license check on cluster create accepts as valid
+func licenseAcceptable(status string, acceptable []string) bool {
+	if len(acceptable) == 0 {
+		return licenseStatusValid(status)
+	}
+
+	return containsFold(acceptable, status)
+}
+
+func supportsKubernetesVersion(clusterProvider *mcaasapi.ClusterProvider, version string) bool {
+	for _, v := range append(append([]string{}, clusterProvider.KubernetesVersions...), clusterProvider.K8sVersions...) {
+		if v == version {
+			return true
+		}
+	}
+
+	return false
+}
+
+func storageClassMatches(sc *mcaasapi.StorageClass, criteria clusterProviderCriteria) bool {
+	if criteria.accessProtocol != "" && !strings.EqualFold(sc.AccessProtocol, criteria.accessProtocol) {
+		return false
+	}
+
+	if criteria.encryption != "" && !strings.EqualFold(sc.Encryption, criteria.encryption) {
+		return false
+	}
+
+	if criteria.dedupe != "" && !strings.EqualFold(sc.Dedupe, criteria.dedupe) {
+		return false
+	}
+
+	if criteria.minIOPS > 0 {
+		iops, err := parseNumber(sc.Iops)
+		if err != nil || iops < criteria.minIOPS {
+			return false
+		}
+	}
+
+	return true
+}
+
+// clusterProviderRanksHigher returns true if a has more available capacity than b
+func clusterProviderRanksHigher(a, b *mcaasapi.ClusterProvider) bool {
+	capacityA, capacityB := a.AvailableCapacity, b.AvailableCapacity
+	if capacityA == nil || capacityB == nil {
+		return capacityA != nil
+	}
+
+	if capacityA.Cpu != capacityB.Cpu {
+		return capacityA.Cpu > capacityB.Cpu
+	}
+
+	if capacityA.Nodes != capacityB.Nodes {
+		return capacityA.Nodes > capacityB.Nodes
+	}
+
+	return capacityA.Clusters > capacityB.Clusters
+}
+
+func writeClusterProviderSelectionValues(d *schema.ResourceData, matches []clusterProviderMatch) error {
+	var err error
+	best := matches[0]
+
+	if err = d.Set("name", best.clusterProvider.Name); err != nil {
+		return err
+	}
+
+	if err = d.Set("cluster_provider_id", best.clusterProvider.Id); err != nil {
+		return err
+	}
+
+	if err = d.Set("storage_class", best.storageClass.Name); err != nil {
+		return err
+	}
+
+	if err = d.Set("kubernetes_versions", best.clusterProvider.KubernetesVersions); err != nil {
+		return err
+	}
+
+	availableCapacity := schemas.FlattenAvailableCapacity(best.clusterProvider.AvailableCapacity)
+	if err = d.Set("available_capacity", availableCapacity); err != nil {
+		return err
+	}
+
+	ranking := make([]string, 0, len(matches))
+	for _, m := range matches {
+		ranking = append(ranking, m.clusterProvider.Name)
+	}
+
+	return d.Set("ranking", ranking)
+}
@@ -0,0 +1,50 @@
+// (C) Copyright 2023 Hewlett Packard Enterprise Development LP
+
+package resources
+
+import (
+	"testing"
+
+	"github.com/HewlettPackard/hpegl-containers-go-sdk/pkg/mcaasapi"
+)
+
+func TestMatchClusterProviderLicense(t *testing.T) {
+	testCases := []struct {
+		name       string
+		status     string
+		acceptable []string
+		reason     string
+	}{
+		{name: "ok", status: "ok"},
+		{name: "valid", status: "Valid"},
+		{name: "active", status: "ACTIVE"},
+		{name: "expired", status: "expired", reason: "license base status is expired"},
+		{name: "expiring", status: "expiring", reason: "license base status is expiring"},
+		{name: "unknown", status: "pending", reason: "license base status is pending"},
+		{name: "acceptable", status: "expiring", acceptable: []string{"ok", "Expiring"}},
+		{name: "not acceptable", status: "valid", acceptable: []string{"ok"}, reason: "license base status is valid"},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			clusterProvider := &mcaasapi.ClusterProvider{
+				Name:   "provider",
+				Health: "ok",
+				LicenseInfo: &mcaasapi.ClusterProviderLicenseInfo{
+					Licenses: []mcaasapi.Licenses{{Label: "base", Status: tc.status}},
+				},
+				StorageClasses: []mcaasapi.StorageClass{{Name: "gl-sbc-hpe"}},
+			}
+			criteria := clusterProviderCriteria{
+				acceptableHealth:        []string{defaultHealth},
+				acceptableLicenseStatus: tc.acceptable,
+			}
+
+			_, reason := matchClusterProvider(clusterProvider, criteria)
+			if reason != tc.reason {
+				t.Errorf("got reason %q, expected %q", reason, tc.reason)
+			}
+		})
+	}
+}
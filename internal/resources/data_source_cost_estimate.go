@@ -0,0 +1,278 @@
+// (C) Copyright 2023 Hewlett Packard Enterprise Development LP
+
+package resources
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/HewlettPackard/hpegl-containers-go-sdk/pkg/mcaasapi"
+
+	"github.com/HewlettPackard/hpegl-containers-terraform-resources/internal/resources/schemas"
+	"github.com/HewlettPackard/hpegl-containers-terraform-resources/pkg/auth"
+	"github.com/HewlettPackard/hpegl-containers-terraform-resources/pkg/client"
+	"github.com/HewlettPackard/hpegl-containers-terraform-resources/pkg/constants"
+)
+
+// numberPattern matches the number in values such as "$0.12/GB" or "5000 IOPS"
+var numberPattern = regexp.MustCompile(`[0-9]*\.?[0-9]+`)
+
+// costEstimate is the estimate for one machine set, or the total for all of them
+type costEstimate struct {
+	name               string
+	machineBlueprintID string
+	size               string
+	count              int32
+	cpu                int32
+	memory             int32
+	disk               int32
+	monthlyStorageCost float64
+}
+
+func DataSourceCostEstimate() *schema.Resource {
+	return &schema.Resource{
+		Schema:             schemas.CostEstimate(),
+		ReadContext:        dataSourceCostEstimateReadContext,
+		SchemaVersion:      0,
+		StateUpgraders:     nil,
+		CustomizeDiff:      nil,
+		Importer:           nil,
+		DeprecationMessage: "",
+		Timeouts:           nil,
+		Description: `CostEstimate data source estimates the resources and monthly storage cost
+			of a cluster.  The required inputs are site_id, storage_class and one of
+			cluster_blueprint_id, whose machine sets are estimated at their min_size, or
+			machine_sets, a list of name, machine_blueprint_id and count.  site_id defaults
+			to the site_id of the caas provider block.  The storage
+			class is looked up in cluster_provider, which defaults to the cluster
+			provider of the blueprint, or to the first cluster provider on the site with
+			that storage class.  cpu, memory and disk (root, ephemeral and persistent)
+			come from the size of each machine blueprint, and the monthly storage cost is
+			disk multiplied by the cost_per_gb of the storage class.  If the storage class
+			has no cost_per_gb the sizes are still estimated, with a warning and a monthly
+			storage cost of 0.  The estimate for
+			each machine set is in machine_set_estimates and the overall one in total.`,
+	}
+}
+
+// nolint: cyclop
+func dataSourceCostEstimateReadContext(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c, err := client.GetClientFromMetaMap(meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	token, err := auth.GetToken(ctx, meta)
+	if err != nil {
+		return diag.Errorf("Error in getting token: %s", err)
+	}
+	clientCtx := context.WithValue(ctx, mcaasapi.ContextAccessToken, token)
+
+	siteID, err := setProviderDefault(d, c, constants.SiteID)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	storageClassName := d.Get("storage_class").(string)
+	clusterProviderName := d.Get("cluster_provider").(string)
+
+	var machineSets []mcaasapi.MachineSet
+	if blueprintID := d.Get("cluster_blueprint_id").(string); blueprintID != "" {
+		blueprint, err := getClusterBlueprint(clientCtx, c, siteID, blueprintID)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		if clusterProviderName == "" {
+			clusterProviderName = blueprint.ClusterProvider
+		}
+
+		for _, ms := range blueprint.MachineSets {
+			ms.Count = ms.MinSize
+			machineSets = append(machineSets, ms)
+		}
+	} else {
+		for _, ms := range d.Get("machine_sets").([]interface{}) {
+			msMap := ms.(map[string]interface{})
+			machineSets = append(machineSets, mcaasapi.MachineSet{
+				Name:               msMap["name"].(string),
+				MachineBlueprintId: msMap["machine_blueprint_id"].(string),
+				Count:              int32(msMap["count"].(int)),
+			})
+		}
+	}
+
+	clusterProvider, storageClass, err := findStorageClass(clientCtx, c, siteID, clusterProviderName, storageClassName)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// The size breakdown is still useful without a price, so an unpriced storage class is only a warning
+	var diags diag.Diagnostics
+	costPerGB, err := parseNumber(storageClass.CostPerGB)
+	if err != nil {
+		costPerGB = 0
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Warning,
+			Summary:  fmt.Sprintf("Storage class %s has no cost_per_gb, the monthly storage costs are 0", storageClassName),
+			Detail:   err.Error(),
+		})
+	}
+
+	machineBlueprints := make(map[string]*mcaasapi.MachineBlueprint)
+	for _, ms := range machineSets {
+		if _, ok := machineBlueprints[ms.MachineBlueprintId]; ok {
+			continue
+		}
+
+		machineBlueprints[ms.MachineBlueprintId], err = getMachineBlueprint(clientCtx, c, siteID, ms.MachineBlueprintId)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	estimates, total := estimateCosts(machineSets, machineBlueprints, costPerGB)
+
+	d.SetId(fmt.Sprintf("%s/%s/%d", clusterProvider.Id, storageClassName, schema.HashString(fmt.Sprintf("%v", machineSets))))
+
+	if err = writeCostEstimateValues(d, clusterProvider.Name, costPerGB, estimates, total); err != nil {
+		return append(diags, diag.FromErr(err)...)
+	}
+
+	return diags
+}
+
+// estimateCosts returns the estimate for each machine set and the total, machineBlueprints holds the
+// machine blueprint of each machine set
+func estimateCosts(
+	machineSets []mcaasapi.MachineSet,
+	machineBlueprints map[string]*mcaasapi.MachineBlueprint,
+	costPerGB float64,
+) ([]costEstimate, costEstimate) {
+	estimates := make([]costEstimate, 0, len(machineSets))
+	total := costEstimate{}
+
+	for _, ms := range machineSets {
+		estimate := costEstimate{
+			name:               ms.Name,
+			machineBlueprintID: ms.MachineBlueprintId,
+			count:              ms.Count,
+		}
+
+		if machineBlueprint := machineBlueprints[ms.MachineBlueprintId]; machineBlueprint != nil {
+			estimate.size = machineBlueprint.Size
+
+			if size := machineBlueprint.SizeDetail; size != nil {
+				estimate.cpu = ms.Count * size.Cpu
+				estimate.memory = ms.Count * size.Memory
+				estimate.disk = ms.Count * (size.RootDisk + size.EphemeralDisk + size.PersistentDisk)
+			}
+		}
+		estimate.monthlyStorageCost = roundCost(float64(estimate.disk) * costPerGB)
+
+		total.count += estimate.count
+		total.cpu += estimate.cpu
+		total.memory += estimate.memory
+		total.disk += estimate.disk
+		estimates = append(estimates, estimate)
+	}
+	total.monthlyStorageCost = roundCost(float64(total.disk) * costPerGB)
+
+	return estimates, total
+}
+
+// findStorageClass returns the storage class called name from the cluster provider called
+// clusterProviderName, or from the first cluster provider on the site that has it
+func findStorageClass(
+	clientCtx context.Context,
+	c *client.Client,
+	siteID, clusterProviderName, name string,
+) (*mcaasapi.ClusterProvider, *mcaasapi.StorageClass, error) {
+	clusterProviders, resp, err := c.CaasClient.ClusterProvidersApi.V1AppliancesIdClusterprovidersGet(clientCtx, siteID, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error getting cluster providers: %w", err)
+	}
+	defer resp.Body.Close()
+
+	for i := range clusterProviders.Items {
+		clusterProvider := &clusterProviders.Items[i]
+		if clusterProviderName != "" && clusterProvider.Name != clusterProviderName {
+			continue
+		}
+
+		for j := range clusterProvider.StorageClasses {
+			if clusterProvider.StorageClasses[j].Name == name {
+				return clusterProvider, &clusterProvider.StorageClasses[j], nil
+			}
+		}
+	}
+
+	if clusterProviderName != "" {
+		return nil, nil, fmt.Errorf("storage class '%s' not found in cluster provider '%s' on site '%s'", name, clusterProviderName, siteID)
+	}
+
+	return nil, nil, fmt.Errorf("storage class '%s' not found on site '%s'", name, siteID)
+}
+
+// parseNumber returns the number in a value such as a cost_per_gb or iops, any currency symbol or unit is ignored
+func parseNumber(value string) (float64, error) {
+	number := numberPattern.FindString(strings.ReplaceAll(value, ",", ""))
+	if number == "" {
+		return 0, fmt.Errorf("no number found in '%s'", value)
+	}
+
+	return strconv.ParseFloat(number, 64)
+}
+
+// roundCost rounds a cost to cents
+func roundCost(cost float64) float64 {
+	return math.Round(cost*100) / 100
+}
+
+func writeCostEstimateValues(
+	d *schema.ResourceData,
+	clusterProvider string,
+	costPerGB float64,
+	estimates []costEstimate,
+	total costEstimate,
+) error {
+	var err error
+	if err = d.Set("cluster_provider", clusterProvider); err != nil {
+		return err
+	}
+
+	if err = d.Set("cost_per_gb", costPerGB); err != nil {
+		return err
+	}
+
+	machineSetEstimates := make([]interface{}, 0, len(estimates))
+	for _, e := range estimates {
+		estimate := flattenCostEstimate(e)
+		estimate["name"] = e.name
+		estimate["machine_blueprint_id"] = e.machineBlueprintID
+		estimate["size"] = e.size
+		machineSetEstimates = append(machineSetEstimates, estimate)
+	}
+
+	if err = d.Set("machine_set_estimates", machineSetEstimates); err != nil {
+		return err
+	}
+
+	return d.Set("total", []interface{}{flattenCostEstimate(total)})
+}
+
+func flattenCostEstimate(e costEstimate) map[string]interface{} {
+	return map[string]interface{}{
+		"count":                int(e.count),
+		"cpu":                  int(e.cpu),
+		"memory":               int(e.memory),
+		"disk":                 int(e.disk),
+		"monthly_storage_cost": e.monthlyStorageCost,
+	}
+}
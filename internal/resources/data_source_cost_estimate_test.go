@@ -0,0 +1,132 @@
+// (C) Copyright 2023 Hewlett Packard Enterprise Development LP
+
+package resources
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/HewlettPackard/hpegl-containers-go-sdk/pkg/mcaasapi"
+)
+
+func TestEstimateCosts(t *testing.T) {
+	machineBlueprints := map[string]*mcaasapi.MachineBlueprint{
+		"small-bp": {
+			Size: "small",
+			SizeDetail: &mcaasapi.AllOfMachineBlueprintSizeDetail{
+				Cpu: 2, Memory: 8, RootDisk: 40, EphemeralDisk: 0, PersistentDisk: 10,
+			},
+		},
+		"large-bp": {
+			Size: "large",
+			SizeDetail: &mcaasapi.AllOfMachineBlueprintSizeDetail{
+				Cpu: 8, Memory: 32, RootDisk: 100, EphemeralDisk: 50, PersistentDisk: 0,
+			},
+		},
+		"unsized-bp": {Size: "custom"},
+	}
+
+	testCases := []struct {
+		name        string
+		machineSets []mcaasapi.MachineSet
+		costPerGB   float64
+		estimates   []costEstimate
+		total       costEstimate
+	}{
+		{
+			name: "machine sets",
+			machineSets: []mcaasapi.MachineSet{
+				{Name: "master", MachineBlueprintId: "small-bp", Count: 3},
+				{Name: "worker", MachineBlueprintId: "large-bp", Count: 2},
+			},
+			costPerGB: 0.12,
+			estimates: []costEstimate{
+				{name: "master", machineBlueprintID: "small-bp", size: "small", count: 3, cpu: 6, memory: 24, disk: 150, monthlyStorageCost: 18},
+				{name: "worker", machineBlueprintID: "large-bp", size: "large", count: 2, cpu: 16, memory: 64, disk: 300, monthlyStorageCost: 36},
+			},
+			total: costEstimate{count: 5, cpu: 22, memory: 88, disk: 450, monthlyStorageCost: 54},
+		},
+		{
+			// Costs are rounded to cents, the total is rounded once rather than summing the rounded costs
+			name: "rounding",
+			machineSets: []mcaasapi.MachineSet{
+				{Name: "a", MachineBlueprintId: "small-bp", Count: 1},
+				{Name: "b", MachineBlueprintId: "small-bp", Count: 1},
+			},
+			costPerGB: 0.0011,
+			estimates: []costEstimate{
+				{name: "a", machineBlueprintID: "small-bp", size: "small", count: 1, cpu: 2, memory: 8, disk: 50, monthlyStorageCost: 0.06},
+				{name: "b", machineBlueprintID: "small-bp", size: "small", count: 1, cpu: 2, memory: 8, disk: 50, monthlyStorageCost: 0.06},
+			},
+			total: costEstimate{count: 2, cpu: 4, memory: 16, disk: 100, monthlyStorageCost: 0.11},
+		},
+		{
+			name: "unpriced storage class",
+			machineSets: []mcaasapi.MachineSet{
+				{Name: "worker", MachineBlueprintId: "large-bp", Count: 1},
+			},
+			estimates: []costEstimate{
+				{name: "worker", machineBlueprintID: "large-bp", size: "large", count: 1, cpu: 8, memory: 32, disk: 150},
+			},
+			total: costEstimate{count: 1, cpu: 8, memory: 32, disk: 150},
+		},
+		{
+			name: "no size detail",
+			machineSets: []mcaasapi.MachineSet{
+				{Name: "worker", MachineBlueprintId: "unsized-bp", Count: 4},
+			},
+			costPerGB: 0.12,
+			estimates: []costEstimate{
+				{name: "worker", machineBlueprintID: "unsized-bp", size: "custom", count: 4},
+			},
+			total: costEstimate{count: 4},
+		},
+		{
+			name: "zero count",
+			machineSets: []mcaasapi.MachineSet{
+				{Name: "worker", MachineBlueprintId: "large-bp", Count: 0},
+			},
+			costPerGB: 0.12,
+			estimates: []costEstimate{
+				{name: "worker", machineBlueprintID: "large-bp", size: "large"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			estimates, total := estimateCosts(tc.machineSets, machineBlueprints, tc.costPerGB)
+
+			if !reflect.DeepEqual(estimates, tc.estimates) {
+				t.Errorf("got estimates\n%+v\nexpected\n%+v", estimates, tc.estimates)
+			}
+
+			if total != tc.total {
+				t.Errorf("got total %+v, expected %+v", total, tc.total)
+			}
+		})
+	}
+}
+
+func TestParseNumber(t *testing.T) {
+	testCases := []struct {
+		value    string
+		expected float64
+		valid    bool
+	}{
+		{value: "0.12", expected: 0.12, valid: true},
+		{value: "$0.12/GB", expected: 0.12, valid: true},
+		{value: "1,024.5 USD", expected: 1024.5, valid: true},
+		{value: ".5", expected: 0.5, valid: true},
+		{value: ""},
+		{value: "N/A"},
+	}
+
+	for _, tc := range testCases {
+		got, err := parseNumber(tc.value)
+		if (err == nil) != tc.valid || got != tc.expected {
+			t.Errorf("parseNumber(%q) returned %g, %v, expected %g, valid %t", tc.value, got, err, tc.expected, tc.valid)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+// (C) Copyright 2023 Hewlett Packard Enterprise Development LP
+
+package resources
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/HewlettPackard/hpegl-containers-go-sdk/pkg/mcaasapi"
+
+	"github.com/HewlettPackard/hpegl-containers-terraform-resources/internal/resources/schemas"
+	"github.com/HewlettPackard/hpegl-containers-terraform-resources/pkg/auth"
+	"github.com/HewlettPackard/hpegl-containers-terraform-resources/pkg/client"
+	"github.com/HewlettPackard/hpegl-containers-terraform-resources/pkg/utils"
+)
+
+func DataSourceKubeconfig() *schema.Resource {
+	return &schema.Resource{
+		Schema:             schemas.Kubeconfig(),
+		ReadContext:        dataSourceKubeconfigReadContext,
+		SchemaVersion:      0,
+		StateUpgraders:     nil,
+		CustomizeDiff:      nil,
+		Importer:           nil,
+		DeprecationMessage: "",
+		Timeouts:           nil,
+		Description: `Kubeconfig data source fetches a fresh kubeconfig for a cluster
+			on every read.  The required input is cluster_id.  token_expiry is the
+			expiry time of the kubeconfig token.  If min_remaining_validity is set
+			(a duration such as "30m") the kubeconfig is fetched again until its token
+			is valid for at least that long, and the read fails if it is not.`,
+	}
+}
+
+func dataSourceKubeconfigReadContext(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c, err := client.GetClientFromMetaMap(meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var minValidity time.Duration
+	if v := d.Get("min_remaining_validity").(string); v != "" {
+		minValidity, err = time.ParseDuration(v)
+		if err != nil {
+			return diag.Errorf("Error parsing min_remaining_validity: %s", err)
+		}
+	}
+
+	retryInterval := pollingInterval
+	if c.PollInterval > 0 {
+		retryInterval = c.PollInterval
+	}
+
+	id := d.Get("cluster_id").(string)
+
+	var kubeconfig mcaasapi.Kubeconfig
+	var expiry time.Time
+	for attempt := 1; ; attempt++ {
+		// Get a token on each attempt since the kubeconfig is issued for the caller
+		token, err := auth.GetToken(ctx, meta)
+		if err != nil {
+			return diag.Errorf("Error in getting token: %s", err)
+		}
+		clientCtx := context.WithValue(ctx, mcaasapi.ContextAccessToken, token)
+
+		var resp *http.Response
+		kubeconfig, resp, err = c.CaasClient.KubeConfigApi.V1ClustersIdKubeconfigGet(clientCtx, id)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		resp.Body.Close()
+
+		expiry = getKubeconfigExpiry(ctx, &kubeconfig)
+		if expiry.IsZero() || time.Until(expiry) >= minValidity || attempt >= retryLimit {
+			break
+		}
+
+		tflog.Info(ctx, "kubeconfig token expires too soon, fetching again", map[string]interface{}{
+			"cluster_id":             id,
+			"token_expiry":           expiry.Format(time.RFC3339),
+			"min_remaining_validity": minValidity.String(),
+			"attempt":                attempt,
+		})
+
+		select {
+		case <-ctx.Done():
+			return diag.FromErr(ctx.Err())
+		case <-time.After(retryInterval):
+		}
+	}
+
+	if !expiry.IsZero() && time.Until(expiry) < minValidity {
+		return diag.Errorf("Kubeconfig token for cluster %s expires at %s, less than min_remaining_validity %s from now",
+			id, expiry.Format(time.RFC3339), minValidity)
+	}
+
+	d.SetId(id)
+
+	if err = writeKubeconfigValues(d, kubeconfig.Kubeconfig); err != nil {
+		return diag.FromErr(err)
+	}
+
+	tokenExpiry := ""
+	if !expiry.IsZero() {
+		tokenExpiry = expiry.Format(time.RFC3339)
+	}
+
+	if err = d.Set("token_expiry", tokenExpiry); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+// getKubeconfigExpiry returns the expiry of the kubeconfig token, falling back to the validity returned by
+// the API if the token can't be parsed.  A zero time is returned if neither is available.
+func getKubeconfigExpiry(ctx context.Context, kubeconfig *mcaasapi.Kubeconfig) time.Time {
+	kc, _, err := utils.DecodeKubeconfig(kubeconfig.Kubeconfig)
+	if err == nil {
+		var user *utils.KubeconfigUser
+		if user, err = kc.CurrentUser(); err == nil {
+			var expiry time.Time
+			if expiry, err = utils.TokenExpiry(user.Token); err == nil {
+				return expiry
+			}
+		}
+	}
+
+	tflog.Debug(ctx, "unable to get expiry from kubeconfig token", map[string]interface{}{
+		"error":      err.Error(),
+		"valid_till": kubeconfig.ValidTill.String(),
+	})
+
+	return kubeconfig.ValidTill
+}
@@ -0,0 +1,180 @@
+// (C) Copyright 2023 Hewlett Packard Enterprise Development LP
+
+package resources
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/HewlettPackard/hpegl-containers-go-sdk/pkg/mcaasapi"
+
+	"github.com/HewlettPackard/hpegl-containers-terraform-resources/internal/resources/schemas"
+	"github.com/HewlettPackard/hpegl-containers-terraform-resources/pkg/auth"
+	"github.com/HewlettPackard/hpegl-containers-terraform-resources/pkg/client"
+	"github.com/HewlettPackard/hpegl-containers-terraform-resources/pkg/constants"
+	"github.com/HewlettPackard/hpegl-containers-terraform-resources/pkg/utils"
+)
+
+func DataSourceKubernetesVersions() *schema.Resource {
+	return &schema.Resource{
+		Schema:             schemas.KubernetesVersions(),
+		ReadContext:        dataSourceKubernetesVersionsReadContext,
+		SchemaVersion:      0,
+		StateUpgraders:     nil,
+		CustomizeDiff:      nil,
+		Importer:           nil,
+		DeprecationMessage: "",
+		Timeouts:           nil,
+		Description: `KubernetesVersions data source lists the kubernetes versions supported by a
+			cluster provider, sorted from oldest to newest.  The required inputs are site_id
+			and cluster_provider, site_id defaults to the site_id of the caas provider
+			block.  Versions such as 1.23.13-hpe2 are ordered by their
+			version and then by their HPE build number, a prerelease such as 1.24.0-rc.1
+			is older than 1.24.0.  Set minor (e.g. "1.24") to only
+			list versions of that minor version.  latest is the newest version and
+			latest_per_minor maps each minor version to its newest version.  If upgrade_from
+			is set, upgrades lists the versions it can be upgraded to directly, which are in
+			the same or the next minor version, and upgrade_path lists the versions to
+			upgrade through, one minor version at a time, to get to latest.`,
+	}
+}
+
+func dataSourceKubernetesVersionsReadContext(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c, err := client.GetClientFromMetaMap(meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	token, err := auth.GetToken(ctx, meta)
+	if err != nil {
+		return diag.Errorf("Error in getting token: %s", err)
+	}
+	clientCtx := context.WithValue(ctx, mcaasapi.ContextAccessToken, token)
+
+	siteID, err := setProviderDefault(d, c, constants.SiteID)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	clusterProviderName := d.Get("cluster_provider").(string)
+	clusterProvider, err := getClusterProvider(clientCtx, c, siteID, clusterProviderName)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if clusterProvider == nil {
+		return diag.Errorf("Cluster provider '%s' not found on site '%s'", clusterProviderName, siteID)
+	}
+
+	d.SetId(clusterProvider.Id)
+
+	allVersions := parseKubernetesVersions(ctx, clusterProvider)
+	minor := d.Get("minor").(string)
+
+	versions := make([]string, 0, len(allVersions))
+	latestPerMinor := make(map[string]interface{})
+	var latest *utils.KubernetesVersion
+	for i, v := range allVersions {
+		if minor != "" && v.MinorVersion() != minor {
+			continue
+		}
+
+		versions = append(versions, v.Original)
+		latestPerMinor[v.MinorVersion()] = v.Original
+		latest = &allVersions[i]
+	}
+
+	var upgrades, upgradePath []string
+	if from := d.Get("upgrade_from").(string); from != "" {
+		fromVersion, err := utils.ParseKubernetesVersion(from)
+		if err != nil {
+			return diag.Errorf("Error parsing upgrade_from: %s", err)
+		}
+
+		for _, v := range allVersions {
+			if minor != "" && v.MinorVersion() != minor {
+				continue
+			}
+
+			if v.Compare(fromVersion) > 0 && v.Major == fromVersion.Major && v.Minor <= fromVersion.Minor+1 {
+				upgrades = append(upgrades, v.Original)
+			}
+		}
+
+		if latest != nil {
+			for _, v := range utils.KubernetesUpgradePath(allVersions, fromVersion, *latest) {
+				upgradePath = append(upgradePath, v.Original)
+			}
+		}
+	}
+
+	if err = writeKubernetesVersionsValues(d, versions, latest, latestPerMinor, upgrades, upgradePath); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+// parseKubernetesVersions returns the kubernetes versions of a cluster provider, sorted, versions that
+// can't be parsed are skipped
+func parseKubernetesVersions(ctx context.Context, clusterProvider *mcaasapi.ClusterProvider) []utils.KubernetesVersion {
+	seen := make(map[string]bool)
+	var versions []utils.KubernetesVersion
+
+	for _, version := range append(append([]string{}, clusterProvider.KubernetesVersions...), clusterProvider.K8sVersions...) {
+		if seen[version] {
+			continue
+		}
+		seen[version] = true
+
+		v, err := utils.ParseKubernetesVersion(version)
+		if err != nil {
+			tflog.Warn(ctx, "skipping kubernetes version", map[string]interface{}{
+				"cluster_provider": clusterProvider.Name,
+				"error":            err.Error(),
+			})
+
+			continue
+		}
+
+		versions = append(versions, v)
+	}
+
+	utils.SortKubernetesVersions(versions)
+
+	return versions
+}
+
+func writeKubernetesVersionsValues(
+	d *schema.ResourceData,
+	versions []string,
+	latest *utils.KubernetesVersion,
+	latestPerMinor map[string]interface{},
+	upgrades, upgradePath []string,
+) error {
+	var err error
+	if err = d.Set("versions", versions); err != nil {
+		return err
+	}
+
+	latestVersion := ""
+	if latest != nil {
+		latestVersion = latest.Original
+	}
+
+	if err = d.Set("latest", latestVersion); err != nil {
+		return err
+	}
+
+	if err = d.Set("latest_per_minor", latestPerMinor); err != nil {
+		return err
+	}
+
+	if err = d.Set("upgrades", upgrades); err != nil {
+		return err
+	}
+
+	return d.Set("upgrade_path", upgradePath)
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/HewlettPackard/hpegl-containers-terraform-resources/internal/resources/schemas"
 	"github.com/HewlettPackard/hpegl-containers-terraform-resources/pkg/auth"
 	"github.com/HewlettPackard/hpegl-containers-terraform-resources/pkg/client"
+	"github.com/HewlettPackard/hpegl-containers-terraform-resources/pkg/constants"
 
 	"github.com/HewlettPackard/hpegl-containers-go-sdk/pkg/mcaasapi"
 )
@@ -26,7 +27,8 @@ func DataSourceMachineBlueprint() *schema.Resource {
 		DeprecationMessage: "",
 		Timeouts:           nil,
 		Description: `Machine Blueprint data source allows reading machine blueprint data 
-			based on blueprint name and appliance ID. Required inputs are name and site_id`,
+			based on blueprint name and appliance ID. Required inputs are name and site_id,
+			site_id defaults to the site_id of the caas provider block`,
 	}
 }
 
@@ -44,7 +46,10 @@ func dataSourceMachineBlueprintReadContext(ctx context.Context, d *schema.Resour
 
 	var diags diag.Diagnostics
 
-	applianceID := d.Get("site_id").(string)
+	applianceID, err := setProviderDefault(d, c, constants.SiteID)
+	if err != nil {
+		return diag.FromErr(err)
+	}
 	field := "applianceID eq " + applianceID
 	blueprints, resp, err := c.CaasClient.MachineBlueprintsApi.V1MachineblueprintsGet(clientCtx, field)
 	if err != nil {
@@ -0,0 +1,215 @@
+// (C) Copyright 2023 Hewlett Packard Enterprise Development LP
+
+package resources
+
+import (
+	"context"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/HewlettPackard/hpegl-containers-go-sdk/pkg/mcaasapi"
+
+	"github.com/HewlettPackard/hpegl-containers-terraform-resources/internal/resources/schemas"
+	"github.com/HewlettPackard/hpegl-containers-terraform-resources/pkg/auth"
+	"github.com/HewlettPackard/hpegl-containers-terraform-resources/pkg/client"
+	"github.com/HewlettPackard/hpegl-containers-terraform-resources/pkg/constants"
+)
+
+// catalogSize is a size offered by a compute type of a machine provider
+type catalogSize struct {
+	mcaasapi.SizeDetail
+	computeType     string
+	machineProvider string
+	workerType      string
+}
+
+func DataSourceMachineSizeCatalog() *schema.Resource {
+	return &schema.Resource{
+		Schema:             schemas.MachineSizeCatalog(),
+		ReadContext:        dataSourceMachineSizeCatalogReadContext,
+		SchemaVersion:      0,
+		StateUpgraders:     nil,
+		CustomizeDiff:      nil,
+		Importer:           nil,
+		DeprecationMessage: "",
+		Timeouts:           nil,
+		Description: `MachineSizeCatalog data source lists the sizes, compute types,
+			storage types and worker types a machine blueprint can use on a site.  The
+			required input is site_id, which defaults to the site_id of the caas provider
+			block.  Set min_cpu or min_memory to get the smallest size with at least that
+			cpu and memory in smallest_size.`,
+	}
+}
+
+func dataSourceMachineSizeCatalogReadContext(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c, err := client.GetClientFromMetaMap(meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	token, err := auth.GetToken(ctx, meta)
+	if err != nil {
+		return diag.Errorf("Error in getting token: %s", err)
+	}
+	clientCtx := context.WithValue(ctx, mcaasapi.ContextAccessToken, token)
+
+	siteID, err := setProviderDefault(d, c, constants.SiteID)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	machineProviders, resp, err := c.CaasClient.MachineProvidersApi.V1AppliancesIdMachineprovidersGet(clientCtx, siteID, nil)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer resp.Body.Close()
+
+	machineProviderName := d.Get("machine_provider").(string)
+	computeTypeName := d.Get("compute_type").(string)
+
+	var sizes []catalogSize
+	computeTypes := make(map[string]bool)
+	storageTypes := make(map[string]bool)
+	workerTypes := make(map[string]bool)
+
+	for _, mp := range machineProviders.Items {
+		name, workerType := "", ""
+		if mp.Name != nil {
+			name = string(*mp.Name)
+		}
+		if mp.WorkerType != nil {
+			workerType = string(*mp.WorkerType)
+		}
+
+		if machineProviderName != "" && name != machineProviderName {
+			continue
+		}
+
+		if workerType != "" {
+			workerTypes[workerType] = true
+		}
+
+		for _, st := range mp.StorageInstanceTypes {
+			storageTypes[st] = true
+		}
+
+		for _, ct := range mp.ComputeInstanceTypes {
+			if computeTypeName != "" && ct.Name != computeTypeName {
+				continue
+			}
+			computeTypes[ct.Name] = true
+
+			for _, size := range ct.Sizes {
+				sizes = append(sizes, catalogSize{
+					SizeDetail:      size,
+					computeType:     ct.Name,
+					machineProvider: name,
+					workerType:      workerType,
+				})
+			}
+		}
+	}
+
+	sort.SliceStable(sizes, func(i, j int) bool {
+		return catalogSizeSmaller(sizes[i], sizes[j])
+	})
+
+	d.SetId(siteID)
+
+	if err = writeMachineSizeCatalogValues(d, sizes, computeTypes, storageTypes, workerTypes); err != nil {
+		return diag.FromErr(err)
+	}
+
+	minCPU, minMemory := int32(d.Get("min_cpu").(int)), int32(d.Get("min_memory").(int))
+	if minCPU == 0 && minMemory == 0 {
+		return nil
+	}
+
+	for _, size := range sizes {
+		if size.Cpu >= minCPU && size.Memory >= minMemory {
+			if err = d.Set("smallest_size", []interface{}{flattenCatalogSize(size)}); err != nil {
+				return diag.FromErr(err)
+			}
+
+			return nil
+		}
+	}
+
+	return diag.Errorf("No size on site '%s' has at least %d cpu and %d memory", siteID, minCPU, minMemory)
+}
+
+// catalogSizeSmaller orders sizes by cpu, then memory, then total disk, then name
+func catalogSizeSmaller(a, b catalogSize) bool {
+	if a.Cpu != b.Cpu {
+		return a.Cpu < b.Cpu
+	}
+
+	if a.Memory != b.Memory {
+		return a.Memory < b.Memory
+	}
+
+	diskA := a.RootDisk + a.EphemeralDisk + a.PersistentDisk
+	diskB := b.RootDisk + b.EphemeralDisk + b.PersistentDisk
+	if diskA != diskB {
+		return diskA < diskB
+	}
+
+	return a.Name < b.Name
+}
+
+func flattenCatalogSize(size catalogSize) map[string]interface{} {
+	return map[string]interface{}{
+		"name":             size.Name,
+		"cpu":              int(size.Cpu),
+		"memory":           int(size.Memory),
+		"root_disk":        int(size.RootDisk),
+		"ephemeral_disk":   int(size.EphemeralDisk),
+		"persistent_disk":  int(size.PersistentDisk),
+		"instance_type":    size.InstanceType,
+		"compute_type":     size.computeType,
+		"machine_provider": size.machineProvider,
+		"worker_type":      size.workerType,
+	}
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+func writeMachineSizeCatalogValues(
+	d *schema.ResourceData,
+	sizes []catalogSize,
+	computeTypes, storageTypes, workerTypes map[string]bool,
+) error {
+	var err error
+
+	flattened := make([]interface{}, 0, len(sizes))
+	for _, size := range sizes {
+		flattened = append(flattened, flattenCatalogSize(size))
+	}
+
+	if err = d.Set("sizes", flattened); err != nil {
+		return err
+	}
+
+	if err = d.Set("compute_types", sortedKeys(computeTypes)); err != nil {
+		return err
+	}
+
+	if err = d.Set("storage_types", sortedKeys(storageTypes)); err != nil {
+		return err
+	}
+
+	if err = d.Set("worker_types", sortedKeys(workerTypes)); err != nil {
+		return err
+	}
+
+	return d.Set("smallest_size", nil)
+}
@@ -0,0 +1,97 @@
+// (C) Copyright 2023 Hewlett Packard Enterprise Development LP
+
+package resources
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+
+	"github.com/hewlettpackard/hpegl-provider-lib/pkg/token/common"
+	"github.com/hewlettpackard/hpegl-provider-lib/pkg/token/retrieve"
+
+	"github.com/HewlettPackard/hpegl-containers-go-sdk/pkg/mcaasapi"
+
+	"github.com/HewlettPackard/hpegl-containers-terraform-resources/pkg/client"
+)
+
+const (
+	testClusterID   = "5d1b7f5e-2b0e-4c6a-9d5f-8f0f3c8e1a27"
+	testBlueprintID = "9c3e4b0a-6f1d-4a8e-b2c7-1e5d7f9a3b64"
+	testSiteID      = "ecb6b8a4-3303-4528-96d1-42230335a9ba"
+	testSpaceID     = "8d5dfbc0-f996-4e45-ae34-f7b9ce4ba9a9"
+	otherID         = "0b7d8c2e-4f3a-4e61-9a5b-6c2d1e8f7a90"
+)
+
+// clusterTestState is the state of a cluster with the arguments that are checked for ForceNew
+func clusterTestState() *terraform.InstanceState {
+	return &terraform.InstanceState{
+		ID: testClusterID,
+		Attributes: map[string]string{
+			"id":                 testClusterID,
+			"name":               "test",
+			"blueprint_id":       testBlueprintID,
+			"site_id":            testSiteID,
+			"space_id":           testSpaceID,
+			"kubernetes_version": "1.25.0",
+		},
+	}
+}
+
+// clusterTestConfig is the configuration of the cluster in clusterTestState with overrides applied
+func clusterTestConfig(overrides map[string]interface{}) *terraform.ResourceConfig {
+	raw := map[string]interface{}{
+		"name":               "test",
+		"blueprint_id":       testBlueprintID,
+		"site_id":            testSiteID,
+		"space_id":           testSpaceID,
+		"kubernetes_version": "1.25.0",
+	}
+	for k, v := range overrides {
+		raw[k] = v
+	}
+
+	return terraform.NewResourceConfigRaw(raw)
+}
+
+// machineBlueprintTestConfig is the configuration of a worker machine blueprint with overrides applied
+func machineBlueprintTestConfig(overrides map[string]interface{}) *terraform.ResourceConfig {
+	raw := map[string]interface{}{
+		"name":             "test",
+		"site_id":          testSiteID,
+		"machine_roles":    []interface{}{"worker"},
+		"machine_provider": "vmaas",
+		"worker_type":      "Virtual",
+		"compute_type":     "General Purpose",
+		"size":             "Large",
+		"storage_type":     "General Purpose",
+	}
+	// A nil override leaves the attribute out of the configuration
+	for k, v := range overrides {
+		if v == nil {
+			delete(raw, k)
+
+			continue
+		}
+		raw[k] = v
+	}
+
+	return terraform.NewResourceConfigRaw(raw)
+}
+
+// testMeta returns provider meta with a CaaS client for the API at baseURL
+func testMeta(baseURL string) interface{} {
+	return map[string]interface{}{
+		client.InitialiseClient{}.ServiceName(): &client.Client{
+			CaasClient: mcaasapi.NewAPIClient(&mcaasapi.Configuration{
+				BasePath:      baseURL,
+				DefaultHeader: make(map[string]string),
+			}),
+			APIURL:  baseURL,
+			SpaceID: testSpaceID,
+		},
+		common.TokenRetrieveFunctionKey: retrieve.TokenRetrieveFuncCtx(func(ctx context.Context) (string, error) {
+			return "token", nil
+		}),
+	}
+}
@@ -0,0 +1,192 @@
+// (C) Copyright 2023 Hewlett Packard Enterprise Development LP
+
+package resources
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/HewlettPackard/hpegl-containers-terraform-resources/pkg/utils"
+)
+
+// kubeconfigAttributes are the attributes set by writeKubeconfigValues
+var kubeconfigAttributes = []string{
+	"kubeconfig",
+	"kubeconfig_raw",
+	"kube_host",
+	"cluster_ca_certificate",
+	"kube_token",
+	"client_certificate",
+	"client_key",
+}
+
+// encryptedKubeconfigAttributes are the attributes set when pgp_key is set
+var encryptedKubeconfigAttributes = []string{
+	"encrypted_kubeconfig",
+	"key_fingerprint",
+	"encrypted_kubeconfig_sha256",
+}
+
+// writeClusterKubeconfig sets the kubeconfig values of a cluster.  If pgp_key is set the kubeconfig
+// is only stored encrypted to that key, as encrypted_kubeconfig, and the cleartext values are cleared.
+// Encryption isn't deterministic, so the kubeconfig is only encrypted again when it or pgp_key has
+// changed, as recorded in encrypted_kubeconfig_sha256, otherwise every refresh would change the state.
+func writeClusterKubeconfig(ctx context.Context, d *schema.ResourceData, kubeconfig string) error {
+	pgpKey := d.Get("pgp_key").(string)
+	if pgpKey == "" {
+		if err := clearValues(d, encryptedKubeconfigAttributes); err != nil {
+			return err
+		}
+
+		return writeKubeconfigValues(d, kubeconfig)
+	}
+
+	if err := clearKubeconfigValues(d); err != nil {
+		return err
+	}
+
+	digest := encryptedKubeconfigDigest(kubeconfig, pgpKey)
+	if d.Get("encrypted_kubeconfig").(string) != "" && d.Get("encrypted_kubeconfig_sha256").(string) == digest {
+		return nil
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(kubeconfig)
+	if err != nil {
+		return fmt.Errorf("error decoding kubeconfig: %w", err)
+	}
+
+	publicKey, err := utils.RetrievePGPKey(ctx, pgpKey)
+	if err != nil {
+		return err
+	}
+
+	encrypted, fingerprint, err := utils.EncryptWithPGPKey(raw, publicKey)
+	if err != nil {
+		return err
+	}
+
+	if err = d.Set("encrypted_kubeconfig", encrypted); err != nil {
+		return err
+	}
+
+	if err = d.Set("key_fingerprint", fingerprint); err != nil {
+		return err
+	}
+
+	return d.Set("encrypted_kubeconfig_sha256", digest)
+}
+
+// encryptedKubeconfigDigest returns the SHA-256 of the kubeconfig and the pgp_key it is encrypted to
+func encryptedKubeconfigDigest(kubeconfig, pgpKey string) string {
+	sum := sha256.Sum256([]byte(pgpKey + "\n" + kubeconfig))
+
+	return hex.EncodeToString(sum[:])
+}
+
+// writeKubeconfigValues sets the base64-encoded kubeconfig along with the raw YAML and the decoded
+// connection details, so that the kubernetes, helm and kubectl providers can be configured directly
+func writeKubeconfigValues(d *schema.ResourceData, kubeconfig string) error {
+	var err error
+	if err = d.Set("kubeconfig", kubeconfig); err != nil {
+		return err
+	}
+
+	kc, raw, err := utils.DecodeKubeconfig(kubeconfig)
+	if err != nil {
+		return err
+	}
+
+	if err = d.Set("kubeconfig_raw", raw); err != nil {
+		return err
+	}
+
+	cluster, err := kc.CurrentCluster()
+	if err != nil {
+		return err
+	}
+
+	user, err := kc.CurrentUser()
+	if err != nil {
+		return err
+	}
+
+	caCertificate, err := decodeKubeconfigData("certificate-authority-data", cluster.CertificateAuthorityData)
+	if err != nil {
+		return err
+	}
+
+	clientCertificate, err := decodeKubeconfigData("client-certificate-data", user.ClientCertificateData)
+	if err != nil {
+		return err
+	}
+
+	clientKey, err := decodeKubeconfigData("client-key-data", user.ClientKeyData)
+	if err != nil {
+		return err
+	}
+
+	if err = d.Set("kube_host", cluster.Server); err != nil {
+		return err
+	}
+
+	if err = d.Set("cluster_ca_certificate", caCertificate); err != nil {
+		return err
+	}
+
+	if err = d.Set("kube_token", user.Token); err != nil {
+		return err
+	}
+
+	if err = d.Set("client_certificate", clientCertificate); err != nil {
+		return err
+	}
+
+	if err = d.Set("client_key", clientKey); err != nil {
+		return err
+	}
+
+	return err
+}
+
+// clearKubeconfigValues removes the kubeconfig and the values decoded from it from state
+func clearKubeconfigValues(d *schema.ResourceData) error {
+	return clearValues(d, kubeconfigAttributes)
+}
+
+// clearStoredKubeconfig removes the kubeconfig from state, both in cleartext and encrypted
+func clearStoredKubeconfig(d *schema.ResourceData) error {
+	if err := clearKubeconfigValues(d); err != nil {
+		return err
+	}
+
+	return clearValues(d, encryptedKubeconfigAttributes)
+}
+
+func clearValues(d *schema.ResourceData, keys []string) error {
+	for _, key := range keys {
+		if err := d.Set(key, ""); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// decodeKubeconfigData decodes a base64 *-data entry of a kubeconfig to PEM
+func decodeKubeconfigData(name, data string) (string, error) {
+	if data == "" {
+		return "", nil
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		return "", fmt.Errorf("error decoding kubeconfig %s: %w", name, err)
+	}
+
+	return string(decoded), nil
+}
@@ -0,0 +1,314 @@
+// (C) Copyright 2023 Hewlett Packard Enterprise Development LP
+
+package resources
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/HewlettPackard/hpegl-containers-go-sdk/pkg/mcaasapi"
+
+	"github.com/HewlettPackard/hpegl-containers-terraform-resources/internal/resources/schemas"
+	"github.com/HewlettPackard/hpegl-containers-terraform-resources/pkg/auth"
+	"github.com/HewlettPackard/hpegl-containers-terraform-resources/pkg/client"
+	"github.com/HewlettPackard/hpegl-containers-terraform-resources/pkg/credential"
+	"github.com/HewlettPackard/hpegl-containers-terraform-resources/pkg/utils"
+)
+
+// kubeconfigFileMu serialises changes to kubeconfig files, several resources can share a file
+var kubeconfigFileMu sync.Mutex
+
+func KubeconfigFile() *schema.Resource {
+	return &schema.Resource{
+		Schema:             schemas.KubeconfigFile(),
+		SchemaVersion:      0,
+		StateUpgraders:     nil,
+		CreateContext:      kubeconfigFileCreateContext,
+		ReadContext:        kubeconfigFileReadContext,
+		DeleteContext:      kubeconfigFileDeleteContext,
+		CustomizeDiff:      customizeDiffKubeconfigFile,
+		Importer:           nil,
+		DeprecationMessage: "",
+		Timeouts:           nil,
+		Description: `The kubeconfig file resource writes the kubeconfigs of one or more
+			clusters to a file with 0600 permissions.  The required inputs are path and
+			at least one cluster block with a cluster_id.  If the file already exists
+			the clusters are merged into it as contexts named context_name, which
+			defaults to the context name used by CaaS, other contexts are left as they
+			are.  The create fails if the file already has a cluster, user or context
+			called context_name that wasn't written for the same cluster, the contexts
+			that are written are marked with an hpegl-caas extension.  current_context
+			optionally sets the current context.  Set exec_credential to write
+			kubeconfigs that use the hpegl-caas-credential exec plugin instead of a
+			token.  If the create fails the contexts that were
+			already written are removed again.  On destroy only the contexts written by
+			this resource are removed, and the file is deleted if it was created by this
+			resource and no contexts are left in it.`,
+	}
+}
+
+func kubeconfigFileCreateContext(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c, err := client.GetClientFromMetaMap(meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	token, err := auth.GetToken(ctx, meta)
+	if err != nil {
+		return diag.Errorf("Error in getting token: %s", err)
+	}
+	clientCtx := context.WithValue(ctx, mcaasapi.ContextAccessToken, token)
+
+	path, err := utils.ExpandHome(d.Get("path").(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	execCredential := d.Get("exec_credential").(bool)
+
+	// The ID is only set once every cluster has been written, so on failure remove the entries that
+	// were written or they would be left behind
+	written := make(map[string]string)
+	fail := func(err error) diag.Diagnostics {
+		diags := diag.FromErr(err)
+		if rollbackErr := removeKubeconfigEntries(ctx, path, written, d.Get("created_file").(bool)); rollbackErr != nil {
+			diags = append(diags, diag.Errorf("Error removing the kubeconfig contexts already written to %s: %s", path, rollbackErr)...)
+		}
+
+		return diags
+	}
+
+	clusters := d.Get("cluster").([]interface{})
+	for i, cl := range clusters {
+		clusterMap := cl.(map[string]interface{})
+		clusterID := clusterMap["cluster_id"].(string)
+
+		kubeconfig, resp, err := c.CaasClient.KubeConfigApi.V1ClustersIdKubeconfigGet(clientCtx, clusterID)
+		if err != nil {
+			return fail(fmt.Errorf("error getting kubeconfig for cluster %s: %w", clusterID, err))
+		}
+		resp.Body.Close()
+
+		kc, _, err := utils.DecodeKubeconfig(kubeconfig.Kubeconfig)
+		if err != nil {
+			return fail(err)
+		}
+
+		if execCredential {
+			var execKubeconfig string
+			execKubeconfig, err = credential.ExecKubeconfig(kubeconfig.Kubeconfig, clusterID, c.APIURL)
+			if err != nil {
+				return fail(err)
+			}
+
+			if kc, err = utils.ParseKubeconfig([]byte(execKubeconfig)); err != nil {
+				return fail(err)
+			}
+		}
+
+		contextName := clusterMap["context_name"].(string)
+		if contextName == "" {
+			contextName = kc.CurrentContext
+		}
+		if contextName == "" {
+			contextName = clusterID
+		}
+		clusterMap["context_name"] = contextName
+		clusters[i] = clusterMap
+
+		// Defaulted context names aren't known at plan time, so check for duplicates here as well
+		if _, ok := written[contextName]; ok {
+			return fail(fmt.Errorf("more than one cluster is written to context %s", contextName))
+		}
+
+		if err = mergeKubeconfigFile(ctx, d, path, contextName, kc, clusterMap["namespace"].(string), clusterID); err != nil {
+			return fail(err)
+		}
+		written[contextName] = clusterID
+	}
+
+	if err = d.Set("cluster", clusters); err != nil {
+		return fail(err)
+	}
+
+	d.SetId(path)
+
+	return nil
+}
+
+// mergeKubeconfigFile adds the current cluster and user of kc to the file at path as contextName, owned by clusterID
+func mergeKubeconfigFile(
+	ctx context.Context,
+	d *schema.ResourceData,
+	path, contextName string,
+	kc *utils.Kubeconfig,
+	namespace, clusterID string,
+) error {
+	cluster, err := kc.CurrentCluster()
+	if err != nil {
+		return err
+	}
+
+	user, err := kc.CurrentUser()
+	if err != nil {
+		return err
+	}
+
+	kubeconfigFileMu.Lock()
+	defer kubeconfigFileMu.Unlock()
+
+	file, exists, err := utils.ReadKubeconfigFile(path)
+	if err != nil {
+		return err
+	}
+
+	// Only record that we created the file on the first write
+	if d.Id() == "" && !d.Get("created_file").(bool) {
+		if err = d.Set("created_file", !exists); err != nil {
+			return err
+		}
+	}
+
+	if err = file.SetEntry(contextName, *cluster, *user, namespace, clusterID); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	if current := d.Get("current_context").(string); current != "" {
+		file.CurrentContext = current
+	} else if file.CurrentContext == "" {
+		file.CurrentContext = contextName
+	}
+
+	tflog.Info(ctx, "writing kubeconfig context", map[string]interface{}{
+		"path":    path,
+		"context": contextName,
+	})
+
+	return utils.WriteKubeconfigFile(path, file)
+}
+
+func kubeconfigFileReadContext(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	path, err := utils.ExpandHome(d.Get("path").(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	kubeconfigFileMu.Lock()
+	defer kubeconfigFileMu.Unlock()
+
+	file, exists, err := utils.ReadKubeconfigFile(path)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// If the file or any of our contexts have been removed the kubeconfig needs to be written again
+	if !exists {
+		d.SetId("")
+
+		return nil
+	}
+
+	for _, cl := range d.Get("cluster").([]interface{}) {
+		clusterMap := cl.(map[string]interface{})
+		contextName := clusterMap["context_name"].(string)
+		if owner, _ := file.EntryOwner(contextName); owner != clusterMap["cluster_id"].(string) {
+			tflog.Info(ctx, "kubeconfig context missing, it will be written again", map[string]interface{}{
+				"path":    path,
+				"context": contextName,
+			})
+			d.SetId("")
+
+			return nil
+		}
+	}
+
+	return nil
+}
+
+func kubeconfigFileDeleteContext(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	path, err := utils.ExpandHome(d.Get("path").(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	entries := make(map[string]string)
+	for _, cl := range d.Get("cluster").([]interface{}) {
+		clusterMap := cl.(map[string]interface{})
+		entries[clusterMap["context_name"].(string)] = clusterMap["cluster_id"].(string)
+	}
+
+	if err = removeKubeconfigEntries(ctx, path, entries, d.Get("created_file").(bool)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+
+	return nil
+}
+
+// removeKubeconfigEntries removes the entries, a map of context name to owning cluster ID, from the file at
+// path.  Entries that are no longer owned by the cluster are left alone.  If createdFile is true and no
+// contexts are left the file is removed.
+func removeKubeconfigEntries(ctx context.Context, path string, entries map[string]string, createdFile bool) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	kubeconfigFileMu.Lock()
+	defer kubeconfigFileMu.Unlock()
+
+	file, exists, err := utils.ReadKubeconfigFile(path)
+	if err != nil || !exists {
+		return err
+	}
+
+	for contextName, clusterID := range entries {
+		if !file.RemoveOwnedEntry(contextName, clusterID) {
+			tflog.Info(ctx, "kubeconfig context not removed, it isn't owned by the cluster", map[string]interface{}{
+				"path":       path,
+				"context":    contextName,
+				"cluster_id": clusterID,
+			})
+		}
+	}
+
+	if createdFile && len(file.Contexts) == 0 {
+		if err = os.Remove(path); err != nil {
+			return fmt.Errorf("error removing kubeconfig %s: %w", path, err)
+		}
+
+		return nil
+	}
+
+	return utils.WriteKubeconfigFile(path, file)
+}
+
+// customizeDiffKubeconfigFile checks that no two cluster blocks set the same context_name
+func customizeDiffKubeconfigFile(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown("cluster") {
+		return nil
+	}
+
+	names := make(map[string]bool)
+	for _, cl := range d.Get("cluster").([]interface{}) {
+		clusterMap, ok := cl.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		contextName := clusterMap["context_name"].(string)
+		if contextName == "" {
+			continue
+		}
+		if names[contextName] {
+			return fmt.Errorf("cluster: more than one block has context_name %s", contextName)
+		}
+		names[contextName] = true
+	}
+
+	return nil
+}
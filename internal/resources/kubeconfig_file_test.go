@@ -0,0 +1,330 @@
+// (C) Copyright 2023 Hewlett Packard Enterprise Development LP
+
+package resources
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+
+	"github.com/HewlettPackard/hpegl-containers-terraform-resources/internal/resources/schemas"
+	"github.com/HewlettPackard/hpegl-containers-terraform-resources/pkg/utils"
+)
+
+const (
+	kubeconfigClusterA = "5d1b7f5e-2b0e-4c6a-9d5f-8f0f3c8e1a27"
+	kubeconfigClusterB = "3f31daa9-9777-4c06-a4d0-e49215f5e48c"
+	kubeconfigMissing  = "233eead2-20de-47ab-b266-2413cdaa3685"
+)
+
+// userKubeconfig is a kubeconfig that the user already had before any CaaS contexts were merged into it
+const userKubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: prod
+  cluster:
+    server: https://prod.example.com:6443
+contexts:
+- name: prod
+  context:
+    cluster: prod
+    user: prod
+users:
+- name: prod
+  user:
+    token: prod-token
+current-context: prod
+`
+
+// testKubeconfigServer returns a CaaS API that serves a kubeconfig for clusters A and B, and fails the
+// kubeconfig request for any other cluster
+func testKubeconfigServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for _, id := range []string{kubeconfigClusterA, kubeconfigClusterB} {
+			if r.URL.Path != "/v1/clusters/"+id+"/kubeconfig" {
+				continue
+			}
+
+			kubeconfig := fmt.Sprintf(`apiVersion: v1
+kind: Config
+clusters:
+- name: %[1]s
+  cluster:
+    server: https://%[1]s.example.com:6443
+contexts:
+- name: %[1]s
+  context:
+    cluster: %[1]s
+    user: %[1]s
+users:
+- name: %[1]s
+  user:
+    token: %[1]s-token
+current-context: %[1]s
+`, id)
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(map[string]string{
+				"kubeconfig": base64.StdEncoding.EncodeToString([]byte(kubeconfig)),
+			})
+
+			return
+		}
+
+		http.Error(w, "internal error", http.StatusInternalServerError)
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func testKubeconfigFileData(t *testing.T, path string, clusters ...map[string]interface{}) *schema.ResourceData {
+	t.Helper()
+
+	cluster := make([]interface{}, 0, len(clusters))
+	for _, c := range clusters {
+		cluster = append(cluster, c)
+	}
+
+	return schema.TestResourceDataRaw(t, schemas.KubeconfigFile(), map[string]interface{}{
+		"path":    path,
+		"cluster": cluster,
+	})
+}
+
+func readTestKubeconfig(t *testing.T, path string) *utils.Kubeconfig {
+	t.Helper()
+
+	kc, exists, err := utils.ReadKubeconfigFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !exists {
+		t.Fatalf("%s doesn't exist", path)
+	}
+
+	return kc
+}
+
+func contextNames(kc *utils.Kubeconfig) []string {
+	names := make([]string, 0, len(kc.Contexts))
+	for _, c := range kc.Contexts {
+		names = append(names, c.Name)
+	}
+
+	return names
+}
+
+func TestKubeconfigFileCreate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(path, []byte(userKubeconfig), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	meta := testMeta(testKubeconfigServer(t).URL)
+
+	d := testKubeconfigFileData(t, path,
+		map[string]interface{}{"cluster_id": kubeconfigClusterA},
+		map[string]interface{}{"cluster_id": kubeconfigClusterB, "context_name": "b"},
+	)
+	if diags := kubeconfigFileCreateContext(context.Background(), d, meta); diags.HasError() {
+		t.Fatal(diags)
+	}
+
+	if d.Id() != path || d.Get("created_file").(bool) {
+		t.Errorf("got ID %s created_file %t, expected %s and false", d.Id(), d.Get("created_file").(bool), path)
+	}
+	if name := d.Get("cluster.0.context_name").(string); name != kubeconfigClusterA {
+		t.Errorf("context_name defaulted to %s, expected the context name of the kubeconfig", name)
+	}
+
+	kc := readTestKubeconfig(t, path)
+	if names := strings.Join(contextNames(kc), ","); names != "prod,"+kubeconfigClusterA+",b" {
+		t.Errorf("got contexts %s", names)
+	}
+	if owner, _ := kc.EntryOwner("b"); owner != kubeconfigClusterB {
+		t.Errorf("b is owned by %q, expected %s", owner, kubeconfigClusterB)
+	}
+	if kc.CurrentContext != "prod" {
+		t.Errorf("current context changed to %s", kc.CurrentContext)
+	}
+}
+
+func TestKubeconfigFileCreateRollback(t *testing.T) {
+	testCases := []struct {
+		name     string
+		existing bool
+		clusters []map[string]interface{}
+		error    string
+	}{
+		{
+			name:     "kubeconfig request fails",
+			existing: true,
+			clusters: []map[string]interface{}{
+				{"cluster_id": kubeconfigClusterA, "context_name": "a"},
+				{"cluster_id": kubeconfigMissing, "context_name": "missing"},
+			},
+			error: "error getting kubeconfig for cluster " + kubeconfigMissing,
+		},
+		{
+			name:     "context not owned",
+			existing: true,
+			clusters: []map[string]interface{}{
+				{"cluster_id": kubeconfigClusterA, "context_name": "a"},
+				{"cluster_id": kubeconfigClusterB, "context_name": "prod"},
+			},
+			error: "kubeconfig already has a cluster, user or context called prod",
+		},
+		{
+			name: "created file",
+			clusters: []map[string]interface{}{
+				{"cluster_id": kubeconfigClusterA, "context_name": "a"},
+				{"cluster_id": kubeconfigMissing, "context_name": "missing"},
+			},
+			error: "error getting kubeconfig for cluster " + kubeconfigMissing,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config")
+			if tc.existing {
+				if err := os.WriteFile(path, []byte(userKubeconfig), 0o600); err != nil {
+					t.Fatal(err)
+				}
+			}
+			meta := testMeta(testKubeconfigServer(t).URL)
+
+			d := testKubeconfigFileData(t, path, tc.clusters...)
+			diags := kubeconfigFileCreateContext(context.Background(), d, meta)
+			if len(diags) != 1 || !strings.Contains(diags[0].Summary, tc.error) {
+				t.Fatalf("got %v, expected a single error containing %q", diags, tc.error)
+			}
+			if d.Id() != "" {
+				t.Errorf("ID set to %s after a failed create", d.Id())
+			}
+
+			if !tc.existing {
+				if _, err := os.Stat(path); !os.IsNotExist(err) {
+					t.Errorf("the kubeconfig created by the failed create was not removed: %v", err)
+				}
+
+				return
+			}
+
+			kc := readTestKubeconfig(t, path)
+			if names := contextNames(kc); len(names) != 1 || names[0] != "prod" {
+				t.Errorf("got contexts %v, expected only prod to be left", names)
+			}
+			if len(kc.Clusters) != 1 || kc.Clusters[0].Cluster.Server != "https://prod.example.com:6443" {
+				t.Errorf("prod cluster changed: %+v", kc.Clusters)
+			}
+		})
+	}
+}
+
+func TestKubeconfigFileDelete(t *testing.T) {
+	testCases := []struct {
+		name        string
+		createdFile bool
+		// userEntries adds the prod entries to the file after the create
+		userEntries bool
+		// otherOwner writes context b for another cluster after the create
+		otherOwner bool
+		contexts   []string
+	}{
+		{name: "existing file", userEntries: true, contexts: []string{"prod"}},
+		{name: "context owned by another cluster", userEntries: true, otherOwner: true, contexts: []string{"prod", "b"}},
+		{name: "created file", createdFile: true},
+		{name: "created file with other contexts", createdFile: true, userEntries: true, contexts: []string{"prod"}},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config")
+			meta := testMeta(testKubeconfigServer(t).URL)
+
+			d := testKubeconfigFileData(t, path,
+				map[string]interface{}{"cluster_id": kubeconfigClusterA, "context_name": "a"},
+				map[string]interface{}{"cluster_id": kubeconfigClusterB, "context_name": "b"},
+			)
+			if diags := kubeconfigFileCreateContext(context.Background(), d, meta); diags.HasError() {
+				t.Fatal(diags)
+			}
+			if err := d.Set("created_file", tc.createdFile); err != nil {
+				t.Fatal(err)
+			}
+
+			kc := readTestKubeconfig(t, path)
+			if tc.userEntries {
+				user, err := utils.ParseKubeconfig([]byte(userKubeconfig))
+				if err != nil {
+					t.Fatal(err)
+				}
+				kc.Clusters = append(kc.Clusters, user.Clusters...)
+				kc.Users = append(kc.Users, user.Users...)
+				kc.Contexts = append(kc.Contexts, user.Contexts...)
+			}
+			if tc.otherOwner {
+				kc.RemoveEntry("b")
+				err := kc.SetEntry("b", utils.KubeconfigCluster{Server: "https://other.example.com:6443"},
+					utils.KubeconfigUser{Token: "other-token"}, "", kubeconfigMissing)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := utils.WriteKubeconfigFile(path, kc); err != nil {
+				t.Fatal(err)
+			}
+
+			if diags := kubeconfigFileDeleteContext(context.Background(), d, meta); diags.HasError() {
+				t.Fatal(diags)
+			}
+
+			if len(tc.contexts) == 0 {
+				if _, err := os.Stat(path); !os.IsNotExist(err) {
+					t.Errorf("the kubeconfig created by the resource was not removed: %v", err)
+				}
+
+				return
+			}
+
+			kc = readTestKubeconfig(t, path)
+			if names := strings.Join(contextNames(kc), ","); names != strings.Join(tc.contexts, ",") {
+				t.Errorf("got contexts %s, expected %s", names, strings.Join(tc.contexts, ","))
+			}
+			if tc.otherOwner {
+				if owner, _ := kc.EntryOwner("b"); owner != kubeconfigMissing {
+					t.Errorf("b is owned by %q, expected it to be left to %s", owner, kubeconfigMissing)
+				}
+			}
+		})
+	}
+}
+
+func TestKubeconfigFileDuplicateContextName(t *testing.T) {
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"path": "~/.kube/config",
+		"cluster": []interface{}{
+			map[string]interface{}{"cluster_id": kubeconfigClusterA, "context_name": "caas"},
+			map[string]interface{}{"cluster_id": kubeconfigClusterB, "context_name": "caas"},
+		},
+	})
+
+	_, err := KubeconfigFile().Diff(context.Background(), nil, config, nil)
+	if err == nil || !strings.Contains(err.Error(), "more than one block has context_name caas") {
+		t.Errorf("expected a duplicate context_name error, got %v", err)
+	}
+}
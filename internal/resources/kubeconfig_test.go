@@ -0,0 +1,96 @@
+// (C) Copyright 2023 Hewlett Packard Enterprise Development LP
+
+package resources
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"testing"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/HewlettPackard/hpegl-containers-terraform-resources/internal/resources/schemas"
+)
+
+// testPublicKey returns a new base64-encoded binary public key
+func testPublicKey(t *testing.T) string {
+	t.Helper()
+
+	entity, err := openpgp.NewEntity("test", "", "test@example.com", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf := new(bytes.Buffer)
+	if err = entity.Serialize(buf); err != nil {
+		t.Fatal(err)
+	}
+
+	return base64.StdEncoding.EncodeToString(buf.Bytes())
+}
+
+func TestWriteClusterKubeconfigEncrypted(t *testing.T) {
+	kubeconfig := base64.StdEncoding.EncodeToString([]byte("apiVersion: v1\nkind: Config\n"))
+	d := schema.TestResourceDataRaw(t, schemas.Cluster(), map[string]interface{}{"pgp_key": testPublicKey(t)})
+
+	if err := writeClusterKubeconfig(context.Background(), d, kubeconfig); err != nil {
+		t.Fatal(err)
+	}
+	encrypted := d.Get("encrypted_kubeconfig").(string)
+	if encrypted == "" || d.Get("kubeconfig").(string) != "" {
+		t.Fatal("the kubeconfig is not stored encrypted only")
+	}
+
+	// A refresh with the same kubeconfig and key keeps the ciphertext
+	if err := writeClusterKubeconfig(context.Background(), d, kubeconfig); err != nil {
+		t.Fatal(err)
+	}
+	if d.Get("encrypted_kubeconfig").(string) != encrypted {
+		t.Error("the kubeconfig was encrypted again without a change")
+	}
+
+	// A new kubeconfig is encrypted again
+	changed := base64.StdEncoding.EncodeToString([]byte("apiVersion: v1\nkind: Config\ncurrent-context: caas\n"))
+	if err := writeClusterKubeconfig(context.Background(), d, changed); err != nil {
+		t.Fatal(err)
+	}
+	if d.Get("encrypted_kubeconfig").(string) == encrypted {
+		t.Error("a changed kubeconfig was not encrypted again")
+	}
+	encrypted = d.Get("encrypted_kubeconfig").(string)
+
+	// So is the same kubeconfig with a new key
+	if err := d.Set("pgp_key", testPublicKey(t)); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeClusterKubeconfig(context.Background(), d, changed); err != nil {
+		t.Fatal(err)
+	}
+	if d.Get("encrypted_kubeconfig").(string) == encrypted {
+		t.Error("the kubeconfig was not encrypted again with the new pgp_key")
+	}
+}
+
+func TestClearStoredKubeconfig(t *testing.T) {
+	kubeconfig := base64.StdEncoding.EncodeToString([]byte("apiVersion: v1\nkind: Config\n"))
+	d := schema.TestResourceDataRaw(t, schemas.Cluster(), map[string]interface{}{"pgp_key": testPublicKey(t)})
+
+	if err := writeClusterKubeconfig(context.Background(), d, kubeconfig); err != nil {
+		t.Fatal(err)
+	}
+	if d.Get("encrypted_kubeconfig").(string) == "" {
+		t.Fatal("encrypted_kubeconfig not set")
+	}
+
+	if err := clearStoredKubeconfig(d); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, key := range append(append([]string{}, kubeconfigAttributes...), encryptedKubeconfigAttributes...) {
+		if v := d.Get(key).(string); v != "" {
+			t.Errorf("%s is %q after store_kubeconfig was turned off", key, v)
+		}
+	}
+}
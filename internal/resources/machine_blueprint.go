@@ -6,6 +6,7 @@ import (
 	"github.com/HewlettPackard/hpegl-containers-terraform-resources/pkg/utils"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 
 	"github.com/HewlettPackard/hpegl-containers-go-sdk/pkg/mcaasapi"
@@ -13,6 +14,7 @@ import (
 	"github.com/HewlettPackard/hpegl-containers-terraform-resources/internal/resources/schemas"
 	"github.com/HewlettPackard/hpegl-containers-terraform-resources/pkg/auth"
 	"github.com/HewlettPackard/hpegl-containers-terraform-resources/pkg/client"
+	"github.com/HewlettPackard/hpegl-containers-terraform-resources/pkg/constants"
 )
 
 func MachineBlueprint() *schema.Resource {
@@ -24,15 +26,21 @@ func MachineBlueprint() *schema.Resource {
 		ReadContext:    machineBlueprintReadContext,
 		// TODO figure out if and how a blueprint can be updated
 		// Update:             machineBlueprintUpdate,
-		DeleteContext:      machineBlueprintDeleteContext,
-		CustomizeDiff:      nil,
+		DeleteContext: machineBlueprintDeleteContext,
+		CustomizeDiff: customdiff.All(
+			customizeDiffProviderDefaults(constants.SiteID),
+			customizeDiffMachineBlueprint,
+		),
 		Importer:           nil,
 		DeprecationMessage: "",
 		Timeouts:           nil,
 		Description: `The machine blueprint resource facilitates the creation and
 			deletion of a CaaS machine blueprint.  Update is currently not supported. The
 			required inputs when creating a cluster blueprint are name,
-			site-id, machine_provider, machine_roles, compute_type, size and storage_type`,
+			site-id, machine_provider, machine_roles, compute_type, size and storage_type.
+			worker_type must be Virtual or Physical for the worker role, and is omitted
+			otherwise.
+			site_id defaults to the site_id of the caas provider block.`,
 	}
 }
 
@@ -204,3 +212,38 @@ func machineBlueprintDeleteContext(ctx context.Context, d *schema.ResourceData,
 	return diags
 
 }
+
+// customizeDiffMachineBlueprint checks the rules between machine_roles and worker_type
+func customizeDiffMachineBlueprint(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown("machine_roles") || !d.NewValueKnown("worker_type") {
+		return nil
+	}
+
+	roles := make(map[string]bool)
+	for _, role := range d.Get("machine_roles").([]interface{}) {
+		r, _ := role.(string)
+		if roles[r] {
+			return fmt.Errorf("machine_roles: %s is listed more than once", r)
+		}
+		roles[r] = true
+	}
+
+	if len(roles) == 0 {
+		return fmt.Errorf("machine_roles: at least one role is required")
+	}
+
+	isWorker := roles[string(mcaasapi.WORKER_MachineRolesType)]
+	workerType := d.Get("worker_type").(string)
+
+	if isWorker && workerType == "" {
+		return fmt.Errorf("worker_type: must be %s or %s for a machine blueprint with the %s role",
+			mcaasapi.VIRTUAL_MachineWorkerType, mcaasapi.PHYSICAL_MachineWorkerType, mcaasapi.WORKER_MachineRolesType)
+	}
+
+	if !isWorker && workerType != "" {
+		return fmt.Errorf("worker_type: is only used by machine blueprints with the %s role, omit it",
+			mcaasapi.WORKER_MachineRolesType)
+	}
+
+	return nil
+}
@@ -0,0 +1,85 @@
+// (C) Copyright 2023 Hewlett Packard Enterprise Development LP
+
+package resources
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestMachineBlueprintValidation(t *testing.T) {
+	r := MachineBlueprint()
+
+	testCases := []struct {
+		name      string
+		overrides map[string]interface{}
+		errorText string
+	}{
+		{name: "valid"},
+		{name: "site_id", overrides: map[string]interface{}{"site_id": "blr"}, errorText: "UUID"},
+		{name: "machine_provider", overrides: map[string]interface{}{"machine_provider": "vmass"}, errorText: "expected"},
+		{name: "machine_roles", overrides: map[string]interface{}{"machine_roles": []interface{}{"wroker"}}, errorText: "expected"},
+		{name: "worker_type", overrides: map[string]interface{}{"worker_type": "virtual"}, errorText: "expected"},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			diags := r.Validate(machineBlueprintTestConfig(tc.overrides))
+
+			if tc.errorText == "" {
+				if diags.HasError() {
+					t.Fatalf("unexpected error: %v", diags)
+				}
+
+				return
+			}
+
+			if !diags.HasError() {
+				t.Fatalf("expected an error for %s", tc.name)
+			}
+
+			if !strings.Contains(diags[0].Summary, tc.errorText) {
+				t.Errorf("error %q does not contain %q", diags[0].Summary, tc.errorText)
+			}
+		})
+	}
+}
+
+func TestMachineBlueprintWorkerTypeRole(t *testing.T) {
+	r := MachineBlueprint()
+
+	testCases := []struct {
+		name      string
+		overrides map[string]interface{}
+		errorText string
+	}{
+		{name: "worker", overrides: nil},
+		{name: "controlplane", overrides: map[string]interface{}{"machine_roles": []interface{}{"controlplane", "etcd"}, "worker_type": nil}},
+		{name: "controlplane with empty worker_type", overrides: map[string]interface{}{"machine_roles": []interface{}{"controlplane"}, "worker_type": ""}},
+		{name: "worker without worker_type", overrides: map[string]interface{}{"worker_type": nil}, errorText: "worker_type: must be"},
+		{name: "worker with empty worker_type", overrides: map[string]interface{}{"worker_type": ""}, errorText: "worker_type: must be"},
+		{name: "controlplane with worker_type", overrides: map[string]interface{}{"machine_roles": []interface{}{"controlplane"}}, errorText: "omit it"},
+		{name: "duplicate role", overrides: map[string]interface{}{"machine_roles": []interface{}{"worker", "worker"}}, errorText: "more than once"},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := r.Diff(context.Background(), nil, machineBlueprintTestConfig(tc.overrides), nil)
+
+			if tc.errorText == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+
+				return
+			}
+
+			if err == nil || !strings.Contains(err.Error(), tc.errorText) {
+				t.Errorf("error is %v, expected it to contain %q", err, tc.errorText)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+// (C) Copyright 2023 Hewlett Packard Enterprise Development LP
+
+package resources
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/HewlettPackard/hpegl-containers-terraform-resources/pkg/client"
+	"github.com/HewlettPackard/hpegl-containers-terraform-resources/pkg/constants"
+)
+
+// providerDefault returns the provider-level default for key, space_id or site_id
+func providerDefault(c *client.Client, key string) string {
+	switch key {
+	case constants.SpaceID:
+		return c.SpaceID
+	case constants.SiteID:
+		return c.SiteID
+	}
+
+	return ""
+}
+
+// customizeDiffProviderDefaults returns a CustomizeDiff that sets each of keys to the provider-level
+// default when it isn't set in the configuration.  The effective value is kept in state, so a change to
+// the default shows up in the plan and replaces the resource.
+func customizeDiffProviderDefaults(keys ...string) schema.CustomizeDiffFunc {
+	return func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+		for _, key := range keys {
+			if configured(d, key) {
+				continue
+			}
+
+			c, err := client.GetClientFromMetaMap(meta)
+			if err != nil {
+				return err
+			}
+
+			value := providerDefault(c, key)
+			if value == "" {
+				if d.Get(key).(string) == "" {
+					return fmt.Errorf("%s must be set, either on the resource or in the caas provider block", key)
+				}
+
+				continue
+			}
+
+			if d.Get(key).(string) == value {
+				continue
+			}
+
+			if err = d.SetNew(key, value); err != nil {
+				return err
+			}
+
+			if d.Id() != "" {
+				if err = d.ForceNew(key); err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	}
+}
+
+// configured returns true if key is set in the configuration
+func configured(d *schema.ResourceDiff, key string) bool {
+	rawConfig := d.GetRawConfig()
+	if rawConfig.IsNull() || !rawConfig.IsKnown() || !rawConfig.Type().HasAttribute(key) {
+		_, ok := d.GetOk(key)
+
+		return ok
+	}
+
+	return !rawConfig.GetAttr(key).IsNull()
+}
+
+// setProviderDefault sets key on a data source to the provider-level default when it isn't set in the
+// configuration, and returns the effective value
+func setProviderDefault(d *schema.ResourceData, c *client.Client, key string) (string, error) {
+	if value := d.Get(key).(string); value != "" {
+		return value, nil
+	}
+
+	value := providerDefault(c, key)
+	if value == "" {
+		return "", fmt.Errorf("%s must be set, either on the data source or in the caas provider block", key)
+	}
+
+	return value, d.Set(key, value)
+}
@@ -0,0 +1,94 @@
+// (C) Copyright 2023 Hewlett Packard Enterprise Development LP
+
+package resources
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+
+	"github.com/HewlettPackard/hpegl-containers-terraform-resources/pkg/client"
+)
+
+// rawConfig returns the cty value of a configuration that sets the string arguments in raw and
+// leaves the arguments in unset null
+func rawConfig(raw map[string]interface{}, unset ...string) cty.Value {
+	attrs := make(map[string]cty.Value)
+	for name, v := range raw {
+		if s, ok := v.(string); ok {
+			attrs[name] = cty.StringVal(s)
+		}
+	}
+	for _, name := range unset {
+		attrs[name] = cty.NullVal(cty.String)
+	}
+
+	return cty.ObjectVal(attrs)
+}
+
+func TestClusterProviderDefaults(t *testing.T) {
+	r := Cluster()
+
+	meta := func(spaceID, siteID string) interface{} {
+		return map[string]interface{}{
+			client.InitialiseClient{}.ServiceName(): &client.Client{SpaceID: spaceID, SiteID: siteID},
+		}
+	}
+
+	testCases := []struct {
+		name        string
+		state       *terraform.InstanceState
+		meta        interface{}
+		requiresNew bool
+		errorText   string
+	}{
+		{name: "default unchanged", state: clusterTestState(), meta: meta(testSpaceID, testSiteID)},
+		{name: "default changed", state: clusterTestState(), meta: meta(testSpaceID, otherID), requiresNew: true},
+		{name: "default unset", state: clusterTestState(), meta: meta("", "")},
+		{name: "new cluster", state: nil, meta: meta(testSpaceID, testSiteID)},
+		{name: "new cluster without default", state: nil, meta: meta("", ""), errorText: "must be set"},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			config := clusterTestConfig(nil)
+			delete(config.Raw, "site_id")
+			delete(config.Raw, "space_id")
+			delete(config.Config, "site_id")
+			delete(config.Config, "space_id")
+
+			// Terraform sends the raw config with the state when planning
+			if tc.state != nil {
+				tc.state.RawConfig = rawConfig(config.Raw, "site_id", "space_id")
+			}
+
+			diff, err := r.Diff(context.Background(), tc.state, config, tc.meta)
+			if tc.errorText != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.errorText) {
+					t.Fatalf("expected error containing %q, got %v", tc.errorText, err)
+				}
+
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if tc.state == nil {
+				if diff.Attributes["site_id"] == nil || diff.Attributes["site_id"].New != testSiteID {
+					t.Errorf("site_id is not set to the provider default: %v", diff.Attributes["site_id"])
+				}
+
+				return
+			}
+
+			if diff.RequiresNew() != tc.requiresNew {
+				t.Errorf("diff RequiresNew is %t, expected %t", diff.RequiresNew(), tc.requiresNew)
+			}
+		})
+	}
+}
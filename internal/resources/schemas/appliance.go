@@ -9,8 +9,10 @@ import (
 func Appliance() map[string]*schema.Schema {
 	return map[string]*schema.Schema{
 		"space_id": {
-			Type:     schema.TypeString,
-			Required: true,
+			Type:             schema.TypeString,
+			Optional:         true,
+			Computed:         true,
+			ValidateDiagFunc: ValidateUUID,
 		},
 		"created_date": {
 			Type:     schema.TypeString,
@@ -1,6 +1,9 @@
 package schemas
 
-import "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
 
 // nolint: funlen
 func Cluster() map[string]*schema.Schema {
@@ -23,11 +26,14 @@ func Cluster() map[string]*schema.Schema {
 		},
 		"name": {
 			Type:     schema.TypeString,
+			ForceNew: true,
 			Required: true,
 		},
 		"blueprint_id": {
-			Type:     schema.TypeString,
-			Required: true,
+			Type:             schema.TypeString,
+			ForceNew:         true,
+			Required:         true,
+			ValidateDiagFunc: ValidateUUID,
 		},
 		"kubernetes_version": {
 			Type:     schema.TypeString,
@@ -66,6 +72,13 @@ func Cluster() map[string]*schema.Schema {
 			},
 			Computed: true,
 		},
+		"effective_machine_sets": {
+			Type: schema.TypeList,
+			Elem: &schema.Resource{
+				Schema: EffectiveMachineSets(),
+			},
+			Computed: true,
+		},
 		"api_endpoint": {
 			Type:     schema.TypeString,
 			Computed: true,
@@ -78,16 +91,22 @@ func Cluster() map[string]*schema.Schema {
 			Computed: true,
 		},
 		"site_id": {
-			Type:     schema.TypeString,
-			Required: true,
+			Type:             schema.TypeString,
+			ForceNew:         true,
+			Optional:         true,
+			Computed:         true,
+			ValidateDiagFunc: ValidateUUID,
 		},
 		"appliance_name": {
 			Type:     schema.TypeString,
 			Computed: true,
 		},
 		"space_id": {
-			Type:     schema.TypeString,
-			Required: true,
+			Type:             schema.TypeString,
+			ForceNew:         true,
+			Optional:         true,
+			Computed:         true,
+			ValidateDiagFunc: ValidateUUID,
 		},
 		"default_storage_class": {
 			Type:     schema.TypeString,
@@ -98,6 +117,53 @@ func Cluster() map[string]*schema.Schema {
 			Computed: true,
 		},
 		"kubeconfig": {
+			Type:      schema.TypeString,
+			Computed:  true,
+			Sensitive: true,
+		},
+		"kubeconfig_raw": {
+			Type:      schema.TypeString,
+			Computed:  true,
+			Sensitive: true,
+		},
+		"kube_host": {
+			Type:      schema.TypeString,
+			Computed:  true,
+			Sensitive: true,
+		},
+		"cluster_ca_certificate": {
+			Type:      schema.TypeString,
+			Computed:  true,
+			Sensitive: true,
+		},
+		"kube_token": {
+			Type:      schema.TypeString,
+			Computed:  true,
+			Sensitive: true,
+		},
+		"client_certificate": {
+			Type:      schema.TypeString,
+			Computed:  true,
+			Sensitive: true,
+		},
+		"client_key": {
+			Type:      schema.TypeString,
+			Computed:  true,
+			Sensitive: true,
+		},
+		"pgp_key": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"encrypted_kubeconfig": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"key_fingerprint": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"encrypted_kubeconfig_sha256": {
 			Type:     schema.TypeString,
 			Computed: true,
 		},
@@ -111,8 +177,9 @@ func Cluster() map[string]*schema.Schema {
 						Required: true,
 					},
 					"machine_blueprint_id": {
-						Type:     schema.TypeString,
-						Required: true,
+						Type:             schema.TypeString,
+						Required:         true,
+						ValidateDiagFunc: ValidateUUID,
 					},
 					"min_size": {
 						Type:     schema.TypeFloat,
@@ -125,6 +192,60 @@ func Cluster() map[string]*schema.Schema {
 				},
 			},
 		},
+		"control_plane": {
+			Type:     schema.TypeList,
+			Optional: true,
+			MaxItems: 1,
+			Elem: &schema.Resource{
+				Schema: ControlPlane(),
+			},
+		},
+		"capacity_check": {
+			Type:             schema.TypeString,
+			Optional:         true,
+			Default:          "off",
+			ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice([]string{"off", "warn", "error"}, false)),
+		},
+		"capacity_check_summary": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"ignore_autoscaler_changes": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Default:  false,
+		},
+		"wait_for_health": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Default:  false,
+		},
+		"acceptable_health": {
+			Type:     schema.TypeList,
+			Optional: true,
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+		},
+		"wait_for_api_server": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Default:  false,
+		},
+		"wait_for_completion": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Default:  true,
+		},
+		"store_kubeconfig": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Default:  true,
+		},
+		"exec_kubeconfig": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
 	}
 }
 
@@ -135,8 +256,10 @@ func DataCluster() map[string]*schema.Schema {
 			Required: true,
 		},
 		"space_id": {
-			Type:     schema.TypeString,
-			Required: true,
+			Type:             schema.TypeString,
+			Optional:         true,
+			Computed:         true,
+			ValidateDiagFunc: ValidateUUID,
 		},
 		"state": {
 			Type:     schema.TypeString,
@@ -208,6 +331,53 @@ func DataCluster() map[string]*schema.Schema {
 			Computed: true,
 		},
 		"kubeconfig": {
+			Type:      schema.TypeString,
+			Computed:  true,
+			Sensitive: true,
+		},
+		"kubeconfig_raw": {
+			Type:      schema.TypeString,
+			Computed:  true,
+			Sensitive: true,
+		},
+		"kube_host": {
+			Type:      schema.TypeString,
+			Computed:  true,
+			Sensitive: true,
+		},
+		"cluster_ca_certificate": {
+			Type:      schema.TypeString,
+			Computed:  true,
+			Sensitive: true,
+		},
+		"kube_token": {
+			Type:      schema.TypeString,
+			Computed:  true,
+			Sensitive: true,
+		},
+		"client_certificate": {
+			Type:      schema.TypeString,
+			Computed:  true,
+			Sensitive: true,
+		},
+		"client_key": {
+			Type:      schema.TypeString,
+			Computed:  true,
+			Sensitive: true,
+		},
+		"pgp_key": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"encrypted_kubeconfig": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"key_fingerprint": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"encrypted_kubeconfig_sha256": {
 			Type:     schema.TypeString,
 			Computed: true,
 		},
@@ -7,8 +7,10 @@ import (
 func ClusterBlueprint() map[string]*schema.Schema {
 	return map[string]*schema.Schema{
 		"site_id": {
-			Type:     schema.TypeString,
-			Required: true,
+			Type:             schema.TypeString,
+			Optional:         true,
+			Computed:         true,
+			ValidateDiagFunc: ValidateUUID,
 		},
 		"created_date": {
 			Type:     schema.TypeString,
@@ -22,9 +22,11 @@ func ClusterBlueprintCreate() map[string]*schema.Schema {
 			Required: true,
 		},
 		"site_id": {
-			Type:     schema.TypeString,
-			ForceNew: true,
-			Required: true,
+			Type:             schema.TypeString,
+			ForceNew:         true,
+			Optional:         true,
+			Computed:         true,
+			ValidateDiagFunc: ValidateUUID,
 		},
 		"cluster_provider": {
 			Type:     schema.TypeString,
@@ -47,8 +49,9 @@ func ClusterBlueprintCreate() map[string]*schema.Schema {
 						Required: true,
 					},
 					"machine_blueprint_id": {
-						Type:     schema.TypeString,
-						Required: true,
+						Type:             schema.TypeString,
+						Required:         true,
+						ValidateDiagFunc: ValidateUUID,
 					},
 					"min_size": {
 						Type:     schema.TypeFloat,
@@ -0,0 +1,67 @@
+// (C) Copyright 2023 Hewlett Packard Enterprise Development LP
+
+package schemas
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func ClusterMachines() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"cluster_id": {
+			Type:             schema.TypeString,
+			Required:         true,
+			ValidateDiagFunc: ValidateUUID,
+		},
+		"space_id": {
+			Type:             schema.TypeString,
+			Optional:         true,
+			Computed:         true,
+			ValidateDiagFunc: ValidateUUID,
+		},
+		"roles": {
+			Type:     schema.TypeList,
+			Optional: true,
+			Elem: &schema.Schema{
+				Type:             schema.TypeString,
+				ValidateDiagFunc: ValidateMachineRole,
+			},
+		},
+		"health": {
+			Type:     schema.TypeList,
+			Optional: true,
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+		},
+		"machines": {
+			Type:     schema.TypeList,
+			Computed: true,
+			Elem: &schema.Resource{
+				Schema: ClusterMachine(),
+			},
+		},
+	}
+}
+
+// ClusterMachine is a machine of a cluster together with the machine set it belongs to
+func ClusterMachine() map[string]*schema.Schema {
+	machine := Machines()
+	machine["machine_set"] = &schema.Schema{
+		Type:     schema.TypeString,
+		Computed: true,
+	}
+	machine["machine_roles"] = &schema.Schema{
+		Type: schema.TypeList,
+		Elem: &schema.Schema{
+			Type: schema.TypeString,
+		},
+		Computed: true,
+	}
+	machine["size"] = &schema.Schema{
+		Type:     schema.TypeString,
+		Computed: true,
+	}
+
+	return machine
+}
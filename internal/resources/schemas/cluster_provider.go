@@ -9,9 +9,11 @@ import (
 func ClusterProvider() map[string]*schema.Schema {
 	return map[string]*schema.Schema{
 		"site_id": {
-			Type:     schema.TypeString,
-			Required: true,
-			ForceNew: true,
+			Type:             schema.TypeString,
+			Optional:         true,
+			Computed:         true,
+			ForceNew:         true,
+			ValidateDiagFunc: ValidateUUID,
 		},
 		"created_date": {
 			Type:     schema.TypeString,
@@ -0,0 +1,92 @@
+// (C) Copyright 2023 Hewlett Packard Enterprise Development LP
+
+package schemas
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func ClusterProviderSelection() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"site_id": {
+			Type:             schema.TypeString,
+			Optional:         true,
+			Computed:         true,
+			ValidateDiagFunc: ValidateUUID,
+		},
+		"kubernetes_version": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"acceptable_health": {
+			Type:     schema.TypeList,
+			Optional: true,
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+		},
+		"acceptable_states": {
+			Type:     schema.TypeList,
+			Optional: true,
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+		},
+		"acceptable_license_status": {
+			Type:     schema.TypeList,
+			Optional: true,
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+		},
+		"access_protocol": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"encryption": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"dedupe": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"min_iops": {
+			Type:     schema.TypeInt,
+			Optional: true,
+		},
+		"name": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"cluster_provider_id": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"storage_class": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"kubernetes_versions": {
+			Type: schema.TypeList,
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+			Computed: true,
+		},
+		"available_capacity": {
+			Type: schema.TypeList,
+			Elem: &schema.Resource{
+				Schema: AvailableCapacity(),
+			},
+			Computed: true,
+		},
+		"ranking": {
+			Type: schema.TypeList,
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+			Computed: true,
+		},
+	}
+}
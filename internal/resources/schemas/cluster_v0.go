@@ -0,0 +1,442 @@
+// (C) Copyright 2023 Hewlett Packard Enterprise Development LP
+
+package schemas
+
+import "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+// ClusterV0 is the cluster schema at schema version 0, it is used to decode state written at that
+// version and must not be changed
+// nolint: funlen
+func ClusterV0() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"state": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"health": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"created_date": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"last_update_date": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"name": {
+			Type:     schema.TypeString,
+			Required: true,
+		},
+		"blueprint_id": {
+			Type:     schema.TypeString,
+			Required: true,
+		},
+		"kubernetes_version": {
+			Type:     schema.TypeString,
+			Optional: true,
+			Computed: true,
+		},
+		"cluster_provider": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"default_machine_sets": {
+			Type: schema.TypeList,
+			Elem: &schema.Resource{
+				Schema: machineSetsV0(),
+			},
+			Computed: true,
+		},
+		"default_machine_sets_detail": {
+			Type: schema.TypeList,
+			Elem: &schema.Resource{
+				Schema: machineSetsDetailV0(),
+			},
+			Computed: true,
+		},
+		"machine_sets": {
+			Type: schema.TypeList,
+			Elem: &schema.Resource{
+				Schema: machineSetsV0(),
+			},
+			Computed: true,
+		},
+		"machine_sets_detail": {
+			Type: schema.TypeList,
+			Elem: &schema.Resource{
+				Schema: machineSetsDetailV0(),
+			},
+			Computed: true,
+		},
+		"effective_machine_sets": {
+			Type: schema.TypeList,
+			Elem: &schema.Resource{
+				Schema: effectiveMachineSetsV0(),
+			},
+			Computed: true,
+		},
+		"api_endpoint": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"service_endpoints": {
+			Type: schema.TypeList,
+			Elem: &schema.Resource{
+				Schema: serviceEndpointsV0(),
+			},
+			Computed: true,
+		},
+		"site_id": {
+			Type:     schema.TypeString,
+			Required: true,
+		},
+		"appliance_name": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"space_id": {
+			Type:     schema.TypeString,
+			Required: true,
+		},
+		"default_storage_class": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"default_storage_class_description": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"kubeconfig": {
+			Type:      schema.TypeString,
+			Computed:  true,
+			Sensitive: true,
+		},
+		"kubeconfig_raw": {
+			Type:      schema.TypeString,
+			Computed:  true,
+			Sensitive: true,
+		},
+		"kube_host": {
+			Type:      schema.TypeString,
+			Computed:  true,
+			Sensitive: true,
+		},
+		"cluster_ca_certificate": {
+			Type:      schema.TypeString,
+			Computed:  true,
+			Sensitive: true,
+		},
+		"kube_token": {
+			Type:      schema.TypeString,
+			Computed:  true,
+			Sensitive: true,
+		},
+		"client_certificate": {
+			Type:      schema.TypeString,
+			Computed:  true,
+			Sensitive: true,
+		},
+		"client_key": {
+			Type:      schema.TypeString,
+			Computed:  true,
+			Sensitive: true,
+		},
+		"pgp_key": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"encrypted_kubeconfig": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"key_fingerprint": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"worker_nodes": {
+			Type:     schema.TypeList,
+			Optional: true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"name": {
+						Type:     schema.TypeString,
+						Required: true,
+					},
+					"machine_blueprint_id": {
+						Type:     schema.TypeString,
+						Required: true,
+					},
+					"min_size": {
+						Type:     schema.TypeFloat,
+						Required: true,
+					},
+					"max_size": {
+						Type:     schema.TypeFloat,
+						Required: true,
+					},
+				},
+			},
+		},
+		"ignore_autoscaler_changes": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Default:  false,
+		},
+		"wait_for_health": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Default:  false,
+		},
+		"acceptable_health": {
+			Type:     schema.TypeList,
+			Optional: true,
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+		},
+		"wait_for_api_server": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Default:  false,
+		},
+		"wait_for_completion": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Default:  true,
+		},
+		"store_kubeconfig": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Default:  true,
+		},
+		"exec_kubeconfig": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+	}
+}
+
+func machineSetsV0() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"name": {
+			Type:     schema.TypeString,
+			ForceNew: true,
+			Computed: true,
+		},
+		"machine_blueprint_id": {
+			Type:     schema.TypeString,
+			ForceNew: true,
+			Computed: true,
+		},
+		"min_size": {
+			Type:     schema.TypeFloat,
+			ForceNew: true,
+			Computed: true,
+		},
+		"max_size": {
+			Type:     schema.TypeFloat,
+			ForceNew: true,
+			Computed: true,
+		},
+	}
+}
+
+func effectiveMachineSetsV0() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"name": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"machine_blueprint_id": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"min_size": {
+			Type:     schema.TypeFloat,
+			Computed: true,
+		},
+		"max_size": {
+			Type:     schema.TypeFloat,
+			Computed: true,
+		},
+	}
+}
+
+func machineSetsDetailV0() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"name": {
+			Type:     schema.TypeString,
+			ForceNew: true,
+			Computed: true,
+		},
+		"min_size": {
+			Type:     schema.TypeFloat,
+			ForceNew: true,
+			Computed: true,
+		},
+		"max_size": {
+			Type:     schema.TypeFloat,
+			ForceNew: true,
+			Computed: true,
+		},
+		"machine_provider": {
+			Type:     schema.TypeString,
+			ForceNew: true,
+			Computed: true,
+		},
+		"machine_roles": {
+			Type: schema.TypeList,
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+			ForceNew: true,
+			Computed: true,
+		},
+		"compute_type": {
+			Type:     schema.TypeString,
+			ForceNew: true,
+			Computed: true,
+		},
+		"storage_type": {
+			Type:     schema.TypeString,
+			ForceNew: true,
+			Computed: true,
+		},
+		"size": {
+			Type:     schema.TypeString,
+			ForceNew: true,
+			Computed: true,
+		},
+		"size_detail": {
+			Type: schema.TypeList,
+			Elem: &schema.Resource{
+				Schema: sizeDetailV0(),
+			},
+			ForceNew: true,
+			Computed: true,
+		},
+		"networks": {
+			Type: schema.TypeList,
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+			ForceNew: true,
+			Computed: true,
+		},
+		"proxy": {
+			Type:     schema.TypeString,
+			ForceNew: true,
+			Computed: true,
+		},
+		"machines": {
+			Type: schema.TypeList,
+			Elem: &schema.Resource{
+				Schema: machinesV0(),
+			},
+			ForceNew: true,
+			Computed: true,
+		},
+	}
+}
+
+func serviceEndpointsV0() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"endpoint": {
+			Type:     schema.TypeString,
+			ForceNew: true,
+			Computed: true,
+		},
+		"name": {
+			Type:     schema.TypeString,
+			ForceNew: true,
+			Computed: true,
+		},
+		"namespace": {
+			Type:     schema.TypeString,
+			ForceNew: true,
+			Computed: true,
+		},
+		"type": {
+			Type:     schema.TypeString,
+			ForceNew: true,
+			Computed: true,
+		},
+	}
+}
+
+func sizeDetailV0() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"name": {
+			Type:     schema.TypeString,
+			ForceNew: true,
+			Computed: true,
+		},
+		"cpu": {
+			Type:     schema.TypeInt,
+			ForceNew: true,
+			Computed: true,
+		},
+		"memory": {
+			Type:     schema.TypeInt,
+			ForceNew: true,
+			Computed: true,
+		},
+		"root_disk": {
+			Type:     schema.TypeInt,
+			ForceNew: true,
+			Computed: true,
+		},
+		"ephemeral_disk": {
+			Type:     schema.TypeInt,
+			ForceNew: true,
+			Computed: true,
+		},
+		"persistent_disk": {
+			Type:     schema.TypeInt,
+			ForceNew: true,
+			Computed: true,
+		},
+	}
+}
+
+func machinesV0() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"state": {
+			Type:     schema.TypeString,
+			ForceNew: true,
+			Computed: true,
+		},
+		"health": {
+			Type:     schema.TypeString,
+			ForceNew: true,
+			Computed: true,
+		},
+		"created_date": {
+			Type:     schema.TypeString,
+			ForceNew: true,
+			Computed: true,
+		},
+		"last_update_date": {
+			Type:     schema.TypeString,
+			ForceNew: true,
+			Computed: true,
+		},
+		"name": {
+			Type:     schema.TypeString,
+			ForceNew: true,
+			Computed: true,
+		},
+		"hostname": {
+			Type:     schema.TypeString,
+			ForceNew: true,
+			Computed: true,
+		},
+		"id": {
+			Type:     schema.TypeString,
+			ForceNew: true,
+			Computed: true,
+		},
+	}
+}
@@ -0,0 +1,60 @@
+// (C) Copyright 2023 Hewlett Packard Enterprise Development LP
+
+package schemas
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func ClusterWait() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"cluster_id": {
+			Type:             schema.TypeString,
+			ForceNew:         true,
+			Required:         true,
+			ValidateDiagFunc: ValidateUUID,
+		},
+		"space_id": {
+			Type:             schema.TypeString,
+			ForceNew:         true,
+			Optional:         true,
+			Computed:         true,
+			ValidateDiagFunc: ValidateUUID,
+		},
+		"target_state": {
+			Type:             schema.TypeString,
+			ForceNew:         true,
+			Optional:         true,
+			Default:          "ready",
+			ValidateDiagFunc: ValidateClusterState,
+		},
+		"wait_for_health": {
+			Type:     schema.TypeBool,
+			ForceNew: true,
+			Optional: true,
+			Default:  false,
+		},
+		"acceptable_health": {
+			Type:     schema.TypeList,
+			ForceNew: true,
+			Optional: true,
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+		},
+		"wait_for_api_server": {
+			Type:     schema.TypeBool,
+			ForceNew: true,
+			Optional: true,
+			Default:  false,
+		},
+		"state": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"health": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+	}
+}
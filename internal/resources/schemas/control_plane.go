@@ -0,0 +1,40 @@
+// (C) Copyright 2023 Hewlett Packard Enterprise Development LP
+
+package schemas
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func ControlPlane() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"count": {
+			Type:             schema.TypeInt,
+			Required:         true,
+			ValidateDiagFunc: validation.ToDiagFunc(validateControlPlaneCount),
+		},
+		"machine_blueprint_id": {
+			Type:             schema.TypeString,
+			Optional:         true,
+			Computed:         true,
+			ValidateDiagFunc: ValidateUUID,
+		},
+	}
+}
+
+// validateControlPlaneCount checks that the control plane count is odd so that etcd can keep quorum
+func validateControlPlaneCount(v interface{}, k string) ([]string, []error) {
+	count, ok := v.(int)
+	if !ok {
+		return nil, []error{fmt.Errorf("expected type of %s to be integer", k)}
+	}
+
+	if count < 1 || count%2 == 0 {
+		return nil, []error{fmt.Errorf("%s must be an odd number of 1 or more, got %d", k, count)}
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,102 @@
+// (C) Copyright 2023 Hewlett Packard Enterprise Development LP
+
+package schemas
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func CostEstimate() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"site_id": {
+			Type:             schema.TypeString,
+			Optional:         true,
+			Computed:         true,
+			ValidateDiagFunc: ValidateUUID,
+		},
+		"storage_class": {
+			Type:     schema.TypeString,
+			Required: true,
+		},
+		"cluster_provider": {
+			Type:     schema.TypeString,
+			Optional: true,
+			Computed: true,
+		},
+		"cluster_blueprint_id": {
+			Type:             schema.TypeString,
+			Optional:         true,
+			ExactlyOneOf:     []string{"cluster_blueprint_id", "machine_sets"},
+			ValidateDiagFunc: ValidateUUID,
+		},
+		"machine_sets": {
+			Type:     schema.TypeList,
+			Optional: true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"name": {
+						Type:     schema.TypeString,
+						Required: true,
+					},
+					"machine_blueprint_id": {
+						Type:             schema.TypeString,
+						Required:         true,
+						ValidateDiagFunc: ValidateUUID,
+					},
+					"count": {
+						Type:     schema.TypeInt,
+						Required: true,
+					},
+				},
+			},
+		},
+		"cost_per_gb": {
+			Type:     schema.TypeFloat,
+			Computed: true,
+		},
+		"machine_set_estimates": {
+			Type:     schema.TypeList,
+			Computed: true,
+			Elem: &schema.Resource{
+				Schema: CostEstimateTotals(map[string]*schema.Schema{
+					"name": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+					"machine_blueprint_id": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+					"size": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+				}),
+			},
+		},
+		"total": {
+			Type:     schema.TypeList,
+			Computed: true,
+			Elem: &schema.Resource{
+				Schema: CostEstimateTotals(map[string]*schema.Schema{}),
+			},
+		},
+	}
+}
+
+// CostEstimateTotals adds the resource and cost totals of a cost estimate to s
+func CostEstimateTotals(s map[string]*schema.Schema) map[string]*schema.Schema {
+	for _, name := range []string{"count", "cpu", "memory", "disk"} {
+		s[name] = &schema.Schema{
+			Type:     schema.TypeInt,
+			Computed: true,
+		}
+	}
+
+	s["monthly_storage_cost"] = &schema.Schema{
+		Type:     schema.TypeFloat,
+		Computed: true,
+	}
+
+	return s
+}
@@ -0,0 +1,61 @@
+// (C) Copyright 2023 Hewlett Packard Enterprise Development LP
+
+package schemas
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func Kubeconfig() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"cluster_id": {
+			Type:             schema.TypeString,
+			Required:         true,
+			ValidateDiagFunc: ValidateUUID,
+		},
+		"min_remaining_validity": {
+			Type:             schema.TypeString,
+			Optional:         true,
+			ValidateDiagFunc: ValidateDuration,
+		},
+		"token_expiry": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"kubeconfig": {
+			Type:      schema.TypeString,
+			Computed:  true,
+			Sensitive: true,
+		},
+		"kubeconfig_raw": {
+			Type:      schema.TypeString,
+			Computed:  true,
+			Sensitive: true,
+		},
+		"kube_host": {
+			Type:      schema.TypeString,
+			Computed:  true,
+			Sensitive: true,
+		},
+		"cluster_ca_certificate": {
+			Type:      schema.TypeString,
+			Computed:  true,
+			Sensitive: true,
+		},
+		"kube_token": {
+			Type:      schema.TypeString,
+			Computed:  true,
+			Sensitive: true,
+		},
+		"client_certificate": {
+			Type:      schema.TypeString,
+			Computed:  true,
+			Sensitive: true,
+		},
+		"client_key": {
+			Type:      schema.TypeString,
+			Computed:  true,
+			Sensitive: true,
+		},
+	}
+}
@@ -0,0 +1,59 @@
+// (C) Copyright 2023 Hewlett Packard Enterprise Development LP
+
+package schemas
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func KubeconfigFile() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"path": {
+			Type:     schema.TypeString,
+			ForceNew: true,
+			Required: true,
+		},
+		"cluster": {
+			Type:     schema.TypeList,
+			ForceNew: true,
+			Required: true,
+			MinItems: 1,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"cluster_id": {
+						Type:             schema.TypeString,
+						ForceNew:         true,
+						Required:         true,
+						ValidateDiagFunc: ValidateUUID,
+					},
+					"context_name": {
+						Type:     schema.TypeString,
+						ForceNew: true,
+						Optional: true,
+						Computed: true,
+					},
+					"namespace": {
+						Type:     schema.TypeString,
+						ForceNew: true,
+						Optional: true,
+					},
+				},
+			},
+		},
+		"current_context": {
+			Type:     schema.TypeString,
+			ForceNew: true,
+			Optional: true,
+		},
+		"exec_credential": {
+			Type:     schema.TypeBool,
+			ForceNew: true,
+			Optional: true,
+			Default:  false,
+		},
+		"created_file": {
+			Type:     schema.TypeBool,
+			Computed: true,
+		},
+	}
+}
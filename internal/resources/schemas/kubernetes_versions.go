@@ -0,0 +1,62 @@
+// (C) Copyright 2023 Hewlett Packard Enterprise Development LP
+
+package schemas
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func KubernetesVersions() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"site_id": {
+			Type:             schema.TypeString,
+			Optional:         true,
+			Computed:         true,
+			ValidateDiagFunc: ValidateUUID,
+		},
+		"cluster_provider": {
+			Type:     schema.TypeString,
+			Required: true,
+		},
+		"minor": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"upgrade_from": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"versions": {
+			Type: schema.TypeList,
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+			Computed: true,
+		},
+		"latest": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"latest_per_minor": {
+			Type: schema.TypeMap,
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+			Computed: true,
+		},
+		"upgrades": {
+			Type: schema.TypeList,
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+			Computed: true,
+		},
+		"upgrade_path": {
+			Type: schema.TypeList,
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+			Computed: true,
+		},
+	}
+}
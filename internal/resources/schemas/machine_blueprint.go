@@ -56,9 +56,11 @@ func MachineBlueprint() map[string]*schema.Schema {
 			Computed: true,
 		},
 		"site_id": {
-			Type:     schema.TypeString,
-			Required: true,
-			ForceNew: true,
+			Type:             schema.TypeString,
+			Optional:         true,
+			Computed:         true,
+			ForceNew:         true,
+			ValidateDiagFunc: ValidateUUID,
 		},
 		"worker_type": {
 			Type:     schema.TypeString,
@@ -20,14 +20,16 @@ func MachineBlueprintCreate() map[string]*schema.Schema {
 			Required: true,
 		},
 		"machine_provider": {
-			Type:     schema.TypeString,
-			ForceNew: true,
-			Required: true,
+			Type:             schema.TypeString,
+			ForceNew:         true,
+			Required:         true,
+			ValidateDiagFunc: ValidateMachineProvider,
 		},
 		"machine_roles": {
 			Type: schema.TypeList,
 			Elem: &schema.Schema{
-				Type: schema.TypeString,
+				Type:             schema.TypeString,
+				ValidateDiagFunc: ValidateMachineRole,
 			},
 			ForceNew: true,
 			Required: true,
@@ -56,14 +58,17 @@ func MachineBlueprintCreate() map[string]*schema.Schema {
 			Required: true,
 		},
 		"site_id": {
-			Type:     schema.TypeString,
-			Required: true,
-			ForceNew: true,
+			Type:             schema.TypeString,
+			Optional:         true,
+			Computed:         true,
+			ForceNew:         true,
+			ValidateDiagFunc: ValidateUUID,
 		},
 		"worker_type": {
-			Type:     schema.TypeString,
-			Required: true,
-			ForceNew: true,
+			Type:             schema.TypeString,
+			Optional:         true,
+			ForceNew:         true,
+			ValidateDiagFunc: ValidateWorkerType,
 		},
 	}
 }
@@ -31,6 +31,29 @@ func MachineSets() map[string]*schema.Schema {
 	}
 }
 
+// EffectiveMachineSets is the schema of a machine set that is computed during plan, unlike
+// MachineSets a change is not ForceNew
+func EffectiveMachineSets() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"name": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"machine_blueprint_id": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"min_size": {
+			Type:     schema.TypeFloat,
+			Computed: true,
+		},
+		"max_size": {
+			Type:     schema.TypeFloat,
+			Computed: true,
+		},
+	}
+}
+
 func FlattenMachineSets(machineSet *[]mcaasapi.MachineSet) []interface{} {
 	if machineSet == nil {
 		return nil
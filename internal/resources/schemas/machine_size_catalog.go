@@ -0,0 +1,83 @@
+// (C) Copyright 2023 Hewlett Packard Enterprise Development LP
+
+package schemas
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func MachineSizeCatalog() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"site_id": {
+			Type:             schema.TypeString,
+			Optional:         true,
+			Computed:         true,
+			ValidateDiagFunc: ValidateUUID,
+		},
+		"machine_provider": {
+			Type:             schema.TypeString,
+			Optional:         true,
+			ValidateDiagFunc: ValidateMachineProvider,
+		},
+		"compute_type": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"min_cpu": {
+			Type:     schema.TypeInt,
+			Optional: true,
+		},
+		"min_memory": {
+			Type:     schema.TypeInt,
+			Optional: true,
+		},
+		"sizes": {
+			Type:     schema.TypeList,
+			Computed: true,
+			Elem: &schema.Resource{
+				Schema: CatalogSize(),
+			},
+		},
+		"compute_types": {
+			Type: schema.TypeList,
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+			Computed: true,
+		},
+		"storage_types": {
+			Type: schema.TypeList,
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+			Computed: true,
+		},
+		"worker_types": {
+			Type: schema.TypeList,
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+			Computed: true,
+		},
+		"smallest_size": {
+			Type:     schema.TypeList,
+			Computed: true,
+			Elem: &schema.Resource{
+				Schema: CatalogSize(),
+			},
+		},
+	}
+}
+
+// CatalogSize is a size of the catalog together with the compute type and machine provider it belongs to
+func CatalogSize() map[string]*schema.Schema {
+	size := SizeDetail()
+	for _, name := range []string{"instance_type", "compute_type", "machine_provider", "worker_type"} {
+		size[name] = &schema.Schema{
+			Type:     schema.TypeString,
+			Computed: true,
+		}
+	}
+
+	return size
+}
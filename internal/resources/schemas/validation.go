@@ -0,0 +1,76 @@
+// (C) Copyright 2023 Hewlett Packard Enterprise Development LP
+
+package schemas
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+
+	"github.com/HewlettPackard/hpegl-containers-go-sdk/pkg/mcaasapi"
+)
+
+var (
+	// ValidateUUID checks that an ID is a UUID
+	ValidateUUID = validation.ToDiagFunc(validation.IsUUID)
+
+	// ValidateMachineRole checks that a machine role is one of mcaasapi.MachineRolesType
+	ValidateMachineRole = validation.ToDiagFunc(validation.StringInSlice([]string{
+		string(mcaasapi.WORKER_MachineRolesType),
+		string(mcaasapi.CONTROLPLANE_MachineRolesType),
+		string(mcaasapi.ETCD_MachineRolesType),
+	}, false))
+
+	// ValidateWorkerType checks that a worker type is one of mcaasapi.MachineWorkerType, it may be empty
+	// for machine blueprints without the worker role
+	ValidateWorkerType = validation.ToDiagFunc(validation.StringInSlice([]string{
+		"",
+		string(mcaasapi.VIRTUAL_MachineWorkerType),
+		string(mcaasapi.PHYSICAL_MachineWorkerType),
+	}, false))
+
+	// ValidateMachineProvider checks that a machine provider is one of mcaasapi.MachineProviderName
+	ValidateMachineProvider = validation.ToDiagFunc(validation.StringInSlice([]string{
+		string(mcaasapi.QUAKE_MachineProviderName),
+		string(mcaasapi.MORPHEUS_MachineProviderName),
+		string(mcaasapi.MISTIO_MachineProviderName),
+		string(mcaasapi.EC2_MachineProviderName),
+		string(mcaasapi.VMAAS_MachineProviderName),
+	}, false))
+
+	// ValidateDuration checks that a value is a duration such as "30m" or "1h30m", it may not be negative
+	ValidateDuration = validation.ToDiagFunc(validateDuration)
+
+	// ValidateClusterState checks that a state is one reported for clusters by the CaaS API, these are the
+	// state constants of the resources package
+	ValidateClusterState = validation.ToDiagFunc(validation.StringInSlice([]string{
+		"initializing",
+		"infra-provisioning",
+		"infra-deprovisioning",
+		"creating",
+		"deleting",
+		"ready",
+		"deleted",
+		"updating",
+		"upgrading",
+	}, false))
+)
+
+func validateDuration(i interface{}, k string) ([]string, []error) {
+	v, ok := i.(string)
+	if !ok {
+		return nil, []error{fmt.Errorf("expected type of %s to be string", k)}
+	}
+
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return nil, []error{fmt.Errorf("%s is not a valid duration: %w", k, err)}
+	}
+
+	if d < 0 {
+		return nil, []error{fmt.Errorf("%s must not be negative, got %s", k, v)}
+	}
+
+	return nil, nil
+}
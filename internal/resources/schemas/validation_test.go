@@ -0,0 +1,32 @@
+// (C) Copyright 2023 Hewlett Packard Enterprise Development LP
+
+package schemas
+
+import (
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+)
+
+func TestValidateDuration(t *testing.T) {
+	testCases := []struct {
+		value interface{}
+		valid bool
+	}{
+		{value: "30m", valid: true},
+		{value: "1h30m", valid: true},
+		{value: "0s", valid: true},
+		{value: "90", valid: false},
+		{value: "30 minutes", valid: false},
+		{value: "", valid: false},
+		{value: "-5m", valid: false},
+		{value: 30, valid: false},
+	}
+
+	for _, tc := range testCases {
+		diags := ValidateDuration(tc.value, cty.GetAttrPath("min_remaining_validity"))
+		if diags.HasError() == tc.valid {
+			t.Errorf("%#v valid is %t, expected %t: %v", tc.value, !diags.HasError(), tc.valid, diags)
+		}
+	}
+}
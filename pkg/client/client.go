@@ -4,6 +4,8 @@ package client
 
 import (
 	"fmt"
+	"net/http"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 
@@ -12,6 +14,8 @@ import (
 	"github.com/hewlettpackard/hpegl-provider-lib/pkg/client"
 
 	"github.com/HewlettPackard/hpegl-containers-terraform-resources/pkg/constants"
+	"github.com/HewlettPackard/hpegl-containers-terraform-resources/pkg/profiles"
+	"github.com/HewlettPackard/hpegl-containers-terraform-resources/pkg/recorder"
 )
 
 // keyForGLClientMap is the key in the map[string]interface{} that is passed down by hpegl used to store *Client
@@ -24,6 +28,13 @@ var _ client.Initialisation = (*InitialiseClient)(nil)
 // Client is the client struct that is used by the provider code
 type Client struct {
 	CaasClient *mcaasapi.APIClient
+	// APIURL is the CaaS API URL, it is used when generating exec-based kubeconfigs
+	APIURL string
+	// SpaceID and SiteID are the defaults used by resources and data sources that don't set their own
+	SpaceID string
+	SiteID  string
+	// PollInterval overrides the interval used when polling for cluster state, zero means use the default
+	PollInterval time.Duration
 }
 
 // InitialiseClient is imported by hpegl from each service repo
@@ -39,7 +50,42 @@ func (i InitialiseClient) NewClient(r *schema.ResourceData) (interface{}, error)
 	if err != nil {
 		return nil, nil
 	}
-	apiURL := caasProviderSettings[constants.APIURL].(string)
+	apiURL, _ := caasProviderSettings[constants.APIURL].(string)
+	spaceID, _ := caasProviderSettings[constants.SpaceID].(string)
+	siteID, _ := caasProviderSettings[constants.SiteID].(string)
+
+	// Settings in the caas block take precedence over those of the profile
+	transport := http.DefaultTransport
+	if profileName, _ := caasProviderSettings[constants.Profile].(string); profileName != "" {
+		profile, err := profiles.Get(profileName)
+		if err != nil {
+			return nil, err
+		}
+
+		if apiURL == "" {
+			apiURL = profile.APIURL
+		}
+		if spaceID == "" {
+			spaceID = profile.SpaceID
+		}
+		if siteID == "" {
+			siteID = profile.SiteID
+		}
+
+		tlsConfig, err := profile.TLS.Config()
+		if err != nil {
+			return nil, fmt.Errorf("profile %s: %w", profileName, err)
+		}
+		if tlsConfig != nil {
+			t := http.DefaultTransport.(*http.Transport).Clone()
+			t.TLSClientConfig = tlsConfig
+			transport = t
+		}
+	}
+
+	if apiURL == "" {
+		apiURL = constants.DefaultAPIURL
+	}
 
 	caasCfg := mcaasapi.Configuration{
 		BasePath:      apiURL,
@@ -47,7 +93,23 @@ func (i InitialiseClient) NewClient(r *schema.ResourceData) (interface{}, error)
 		UserAgent:     "hpegl-terraform",
 	}
 
-	cli := new(Client)
+	cli := &Client{APIURL: apiURL, SpaceID: spaceID, SiteID: siteID}
+	if transport != http.DefaultTransport {
+		caasCfg.HTTPClient = &http.Client{Transport: transport}
+	}
+
+	// Record or replay API exchanges if requested, this is used by the acceptance tests
+	rec, err := recorder.FromEnv(transport)
+	if err != nil {
+		return nil, err
+	}
+	if rec != nil {
+		caasCfg.HTTPClient = &http.Client{Transport: rec}
+		if recorder.Replaying() {
+			cli.PollInterval = recorder.ReplayPollInterval
+		}
+	}
+
 	cli.CaasClient = mcaasapi.NewAPIClient(&caasCfg)
 
 	return cli, nil
@@ -0,0 +1,132 @@
+// (C) Copyright 2023 Hewlett Packard Enterprise Development LP
+
+package client
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/HewlettPackard/hpegl-containers-terraform-resources/pkg/constants"
+)
+
+const (
+	testUUID = "233eead2-20de-47ab-b266-2413cdaa3685"
+
+	testProfilesFile = `
+profiles:
+  integ:
+    api_url: https://mcaas.intg.hpedevops.net/mcaas
+    space_id: f866c9bd-2d2c-4e60-aab0-64737df96273
+    site_id: 233eead2-20de-47ab-b266-2413cdaa3685
+  insecure:
+    api_url: https://mcaas.example.com/mcaas
+    tls:
+      insecure_skip_verify: true
+`
+)
+
+// caasBlock is the caas block as hpegl adds it to the provider schema, a set with one element
+var caasBlock = map[string]*schema.Schema{
+	constants.ServiceName: {
+		Type:     schema.TypeSet,
+		Optional: true,
+		MaxItems: 1,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				constants.APIURL:  {Type: schema.TypeString, Optional: true},
+				constants.Profile: {Type: schema.TypeString, Optional: true},
+				constants.SpaceID: {Type: schema.TypeString, Optional: true},
+				constants.SiteID:  {Type: schema.TypeString, Optional: true},
+			},
+		},
+	},
+}
+
+// newTestClient runs NewClient with the caas block set to caas
+func newTestClient(t *testing.T, caas map[string]interface{}) (*Client, error) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "profiles.yaml")
+	if err := os.WriteFile(path, []byte(testProfilesFile), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(constants.ProfilesFileEnvVar, path)
+
+	d := schema.TestResourceDataRaw(t, caasBlock, map[string]interface{}{
+		constants.ServiceName: []interface{}{caas},
+	})
+
+	cli, err := InitialiseClient{}.NewClient(d)
+	if err != nil {
+		return nil, err
+	}
+
+	return cli.(*Client), nil
+}
+
+func TestNewClientProfiles(t *testing.T) {
+	testCases := []struct {
+		name      string
+		caas      map[string]interface{}
+		apiURL    string
+		spaceID   string
+		siteID    string
+		errorText string
+	}{
+		{
+			name:   "no profile",
+			caas:   map[string]interface{}{},
+			apiURL: constants.DefaultAPIURL,
+		},
+		{
+			name:    "profile",
+			caas:    map[string]interface{}{"profile": "integ"},
+			apiURL:  "https://mcaas.intg.hpedevops.net/mcaas",
+			spaceID: "f866c9bd-2d2c-4e60-aab0-64737df96273",
+			siteID:  "233eead2-20de-47ab-b266-2413cdaa3685",
+		},
+		{
+			name:    "caas block overrides profile",
+			caas:    map[string]interface{}{"profile": "integ", "api_url": "https://mcaas.us1.greenlake-hpe.com/mcaas", "site_id": testUUID},
+			apiURL:  "https://mcaas.us1.greenlake-hpe.com/mcaas",
+			spaceID: "f866c9bd-2d2c-4e60-aab0-64737df96273",
+			siteID:  testUUID,
+		},
+		{
+			name:   "tls",
+			caas:   map[string]interface{}{"profile": "insecure"},
+			apiURL: "https://mcaas.example.com/mcaas",
+		},
+		{
+			name:      "unknown profile",
+			caas:      map[string]interface{}{"profile": "eu9"},
+			errorText: "profile eu9 not found, the profiles are insecure, integ",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			c, err := newTestClient(t, tc.caas)
+			if tc.errorText != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.errorText) {
+					t.Fatalf("expected error containing %q, got %v", tc.errorText, err)
+				}
+
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if c.APIURL != tc.apiURL || c.SpaceID != tc.spaceID || c.SiteID != tc.siteID {
+				t.Errorf("got api_url %q space_id %q site_id %q, expected %q %q %q",
+					c.APIURL, c.SpaceID, c.SiteID, tc.apiURL, tc.spaceID, tc.siteID)
+			}
+		})
+	}
+}
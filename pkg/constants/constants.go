@@ -10,4 +10,26 @@ const (
 	// Provider Service Block keys
 	// APIURL - CaaS api_url
 	APIURL = "api_url"
+
+	// APIURLEnvVar - env-var that can be used to set the CaaS api_url
+	APIURLEnvVar = "HPEGL_CAAS_API_URL"
+	// DefaultAPIURL - the CaaS api_url used if none is set
+	DefaultAPIURL = "https://mcaas.us1.greenlake-hpe.com/mcaas"
+
+	// SpaceID - default space_id for CaaS resources and data sources
+	SpaceID = "space_id"
+	// SpaceIDEnvVar - env-var that can be used to set the default space_id
+	SpaceIDEnvVar = "HPEGL_CAAS_SPACE_ID"
+
+	// SiteID - default site_id for CaaS resources and data sources
+	SiteID = "site_id"
+	// SiteIDEnvVar - env-var that can be used to set the default site_id
+	SiteIDEnvVar = "HPEGL_CAAS_SITE_ID"
+
+	// Profile - the name of a profile in the profiles file, it sets api_url, space_id, site_id and TLS settings
+	Profile = "profile"
+	// ProfileEnvVar - env-var that can be used to set the profile
+	ProfileEnvVar = "HPEGL_CAAS_PROFILE"
+	// ProfilesFileEnvVar - env-var holding the path of the profiles file
+	ProfilesFileEnvVar = "HPEGL_CAAS_PROFILES_FILE"
 )
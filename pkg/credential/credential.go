@@ -0,0 +1,251 @@
+// (C) Copyright 2023 Hewlett Packard Enterprise Development LP
+
+// Package credential - a client.authentication.k8s.io exec credential plugin for CaaS clusters.  A GreenLake
+// token is obtained with the same service-client settings as the hpegl provider, and used to fetch a short-lived
+// cluster token from the CaaS kubeconfig API.
+package credential
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/HewlettPackard/hpegl-containers-go-sdk/pkg/mcaasapi"
+
+	"github.com/hewlettpackard/hpegl-provider-lib/pkg/token/retrieve"
+	"github.com/hewlettpackard/hpegl-provider-lib/pkg/token/serviceclient"
+
+	"github.com/HewlettPackard/hpegl-containers-terraform-resources/pkg/utils"
+)
+
+const (
+	// Command - the name of the exec credential plugin binary
+	Command = "hpegl-caas-credential"
+
+	// DefaultExecAPIVersion - the ExecCredential apiVersion used if kubectl doesn't pass one in KUBERNETES_EXEC_INFO
+	DefaultExecAPIVersion = "client.authentication.k8s.io/v1beta1"
+
+	// execInfoEnvVar - env-var set by kubectl and client-go holding the ExecCredential request
+	execInfoEnvVar = "KUBERNETES_EXEC_INFO"
+
+	defaultIAMServiceURL = "https://client.greenlake.hpe.com/api/iam"
+)
+
+// envSettings maps the hpegl provider settings used by serviceclient.NewHandler to the
+// env-vars that are used to set them for the provider
+var envSettings = map[string]string{
+	"iam_service_url":           "HPEGL_IAM_SERVICE_URL",
+	"tenant_id":                 "HPEGL_TENANT_ID",
+	"user_id":                   "HPEGL_USER_ID",
+	"user_secret":               "HPEGL_USER_SECRET",
+	"api_vended_service_client": "HPEGL_API_VENDED_SERVICE_CLIENT",
+	"iam_token":                 "HPEGL_IAM_TOKEN",
+}
+
+// ExecCredential is the object printed by an exec credential plugin
+type ExecCredential struct {
+	APIVersion string               `json:"apiVersion"`
+	Kind       string               `json:"kind"`
+	Status     ExecCredentialStatus `json:"status"`
+}
+
+// ExecCredentialStatus holds the credential returned to kubectl
+type ExecCredentialStatus struct {
+	ExpirationTimestamp   string `json:"expirationTimestamp,omitempty"`
+	Token                 string `json:"token,omitempty"`
+	ClientCertificateData string `json:"clientCertificateData,omitempty"`
+	ClientKeyData         string `json:"clientKeyData,omitempty"`
+}
+
+// envResourceData implements the Get function used by serviceclient.NewHandler, reading values from the env
+type envResourceData struct{}
+
+func (e envResourceData) Get(key string) interface{} {
+	value := os.Getenv(envSettings[key])
+
+	switch key {
+	case "iam_service_url":
+		if value == "" {
+			return defaultIAMServiceURL
+		}
+	case "api_vended_service_client":
+		vended, err := strconv.ParseBool(value)
+		if err != nil {
+			return true
+		}
+
+		return vended
+	}
+
+	return value
+}
+
+// Run fetches a token for clusterID from the CaaS API at apiURL and writes an ExecCredential to out
+func Run(ctx context.Context, clusterID, apiURL string, out io.Writer) error {
+	h, err := serviceclient.NewHandler(envResourceData{})
+	if err != nil {
+		return err
+	}
+
+	token, err := retrieve.NewTokenRetrieveFunc(h)(ctx)
+	if err != nil {
+		return fmt.Errorf("error getting GreenLake token: %w", err)
+	}
+	if token == "" {
+		return errors.New("no GreenLake token returned")
+	}
+
+	caasClient := mcaasapi.NewAPIClient(&mcaasapi.Configuration{
+		BasePath:      apiURL,
+		DefaultHeader: make(map[string]string),
+		UserAgent:     Command,
+	})
+
+	clientCtx := context.WithValue(ctx, mcaasapi.ContextAccessToken, token)
+	kubeconfig, resp, err := caasClient.KubeConfigApi.V1ClustersIdKubeconfigGet(clientCtx, clusterID)
+	if err != nil {
+		return fmt.Errorf("error getting kubeconfig for cluster %s: %w", clusterID, err)
+	}
+	defer resp.Body.Close()
+
+	cred, err := NewExecCredential(&kubeconfig, os.Getenv(execInfoEnvVar))
+	if err != nil {
+		return err
+	}
+
+	return json.NewEncoder(out).Encode(cred)
+}
+
+// NewExecCredential returns the ExecCredential for a kubeconfig returned by the CaaS API.  execInfo is the
+// value of KUBERNETES_EXEC_INFO, the apiVersion requested in it is used for the ExecCredential.
+func NewExecCredential(kubeconfig *mcaasapi.Kubeconfig, execInfo string) (*ExecCredential, error) {
+	kc, _, err := utils.DecodeKubeconfig(kubeconfig.Kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := kc.CurrentUser()
+	if err != nil {
+		return nil, err
+	}
+
+	cred := &ExecCredential{
+		APIVersion: DefaultExecAPIVersion,
+		Kind:       "ExecCredential",
+	}
+
+	if execInfo != "" {
+		var info struct {
+			APIVersion string `json:"apiVersion"`
+		}
+		if err = json.Unmarshal([]byte(execInfo), &info); err != nil {
+			return nil, fmt.Errorf("error parsing %s: %w", execInfoEnvVar, err)
+		}
+		if info.APIVersion != "" {
+			cred.APIVersion = info.APIVersion
+		}
+	}
+
+	switch {
+	case user.Token != "":
+		cred.Status.Token = user.Token
+	case user.ClientCertificateData != "" && user.ClientKeyData != "":
+		cert, err := decodePEM(user.ClientCertificateData)
+		if err != nil {
+			return nil, err
+		}
+		key, err := decodePEM(user.ClientKeyData)
+		if err != nil {
+			return nil, err
+		}
+		cred.Status.ClientCertificateData = cert
+		cred.Status.ClientKeyData = key
+	default:
+		return nil, errors.New("no token or client certificate in kubeconfig")
+	}
+
+	expiry := kubeconfig.ValidTill
+	if user.Token != "" {
+		if tokenExpiry, err := utils.TokenExpiry(user.Token); err == nil {
+			expiry = tokenExpiry
+		}
+	}
+	if !expiry.IsZero() {
+		cred.Status.ExpirationTimestamp = expiry.UTC().Format(time.RFC3339)
+	}
+
+	return cred, nil
+}
+
+// ExecKubeconfig returns a kubeconfig for the cluster in kubeconfig that uses this plugin to get
+// credentials, it holds no secrets
+func ExecKubeconfig(kubeconfig, clusterID, apiURL string) (string, error) {
+	kc, _, err := utils.DecodeKubeconfig(kubeconfig)
+	if err != nil {
+		return "", err
+	}
+
+	cluster, err := kc.CurrentCluster()
+	if err != nil {
+		return "", err
+	}
+
+	name := clusterID
+	if ctx := kc.CurrentContext; ctx != "" {
+		name = ctx
+	}
+
+	execKubeconfig := utils.Kubeconfig{
+		APIVersion: "v1",
+		Kind:       "Config",
+		Clusters: []utils.NamedCluster{{
+			Name: name,
+			Cluster: utils.KubeconfigCluster{
+				Server:                   cluster.Server,
+				CertificateAuthorityData: cluster.CertificateAuthorityData,
+				InsecureSkipTLSVerify:    cluster.InsecureSkipTLSVerify,
+			},
+		}},
+		Contexts: []utils.NamedContext{{
+			Name: name,
+			Context: utils.KubeconfigContext{
+				Cluster: name,
+				User:    name,
+			},
+		}},
+		Users: []utils.NamedUser{{
+			Name: name,
+			User: utils.KubeconfigUser{
+				Exec: &utils.KubeconfigExec{
+					APIVersion:      DefaultExecAPIVersion,
+					Command:         Command,
+					Args:            []string{"--cluster-id", clusterID, "--api-url", apiURL},
+					InteractiveMode: "Never",
+				},
+			},
+		}},
+		CurrentContext: name,
+	}
+
+	out, err := execKubeconfig.Marshal()
+	if err != nil {
+		return "", err
+	}
+
+	return string(out), nil
+}
+
+func decodePEM(data string) (string, error) {
+	decoded, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		return "", fmt.Errorf("error decoding kubeconfig client certificate: %w", err)
+	}
+
+	return string(decoded), nil
+}
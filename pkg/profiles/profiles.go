@@ -0,0 +1,137 @@
+// (C) Copyright 2023 Hewlett Packard Enterprise Development LP
+
+// Package profiles - named CaaS environments read from a YAML profiles file.  A profile is selected with
+// the profile argument of the caas provider block, and holds the api_url, default space_id and site_id and
+// TLS settings of that environment.
+package profiles
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/HewlettPackard/hpegl-containers-terraform-resources/pkg/constants"
+)
+
+// File is the on-disk format of the profiles file, e.g.
+//
+//	profiles:
+//	  integ:
+//	    api_url: https://mcaas.intg.hpedevops.net/mcaas
+//	    space_id: <space-id>
+//	    site_id: <site-id>
+//	    tls:
+//	      ca_file: /etc/ssl/certs/integ-ca.pem
+type File struct {
+	Profiles map[string]Profile `yaml:"profiles"`
+}
+
+// Profile is a named CaaS environment
+type Profile struct {
+	APIURL  string `yaml:"api_url"`
+	SpaceID string `yaml:"space_id"`
+	SiteID  string `yaml:"site_id"`
+	TLS     TLS    `yaml:"tls"`
+}
+
+// TLS holds the TLS settings used to connect to the CaaS API of a profile
+type TLS struct {
+	// InsecureSkipVerify disables verification of the API server certificate
+	InsecureSkipVerify bool `yaml:"insecure_skip_verify"`
+	// CAFile is the path of a PEM file of CA certificates that are trusted as well as the system ones
+	CAFile string `yaml:"ca_file"`
+	// CAData is a PEM encoded CA certificate that is trusted as well as the system ones
+	CAData string `yaml:"ca_data"`
+	// ServerName overrides the name used to verify the API server certificate
+	ServerName string `yaml:"server_name"`
+}
+
+// Load reads the profiles file at path, unknown keys are an error so that typos are not silently ignored
+func Load(path string) (*File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading profiles file: %w", err)
+	}
+
+	f := &File{}
+	if err = yaml.UnmarshalStrict(data, f); err != nil {
+		return nil, fmt.Errorf("error parsing profiles file %s: %w", path, err)
+	}
+
+	return f, nil
+}
+
+// Get returns the profile called name from the profiles file named by constants.ProfilesFileEnvVar
+func Get(name string) (*Profile, error) {
+	path := os.Getenv(constants.ProfilesFileEnvVar)
+	if path == "" {
+		return nil, fmt.Errorf("profile %s is set but %s is not", name, constants.ProfilesFileEnvVar)
+	}
+
+	f, err := Load(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return f.Get(name)
+}
+
+// Get returns the profile called name
+func (f *File) Get(name string) (*Profile, error) {
+	p, ok := f.Profiles[name]
+	if !ok {
+		names := make([]string, 0, len(f.Profiles))
+		for n := range f.Profiles {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+
+		return nil, fmt.Errorf("profile %s not found, the profiles are %s", name, strings.Join(names, ", "))
+	}
+
+	return &p, nil
+}
+
+// Config returns the tls.Config for the TLS settings, or nil if the defaults are to be used
+func (t TLS) Config() (*tls.Config, error) {
+	if t == (TLS{}) {
+		return nil, nil
+	}
+
+	cfg := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: t.InsecureSkipVerify, // nolint: gosec
+		ServerName:         t.ServerName,
+	}
+
+	if t.CAFile == "" && t.CAData == "" {
+		return cfg, nil
+	}
+
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+
+	if t.CAFile != "" {
+		pem, err := os.ReadFile(t.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("error reading ca_file: %w", err)
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in ca_file %s", t.CAFile)
+		}
+	}
+
+	if t.CAData != "" && !pool.AppendCertsFromPEM([]byte(t.CAData)) {
+		return nil, fmt.Errorf("no certificates found in ca_data")
+	}
+	cfg.RootCAs = pool
+
+	return cfg, nil
+}
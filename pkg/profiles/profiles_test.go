@@ -0,0 +1,39 @@
+// (C) Copyright 2023 Hewlett Packard Enterprise Development LP
+
+package profiles
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestTLSConfig(t *testing.T) {
+	cfg, err := TLS{}.Config()
+	if err != nil || cfg != nil {
+		t.Errorf("expected no tls.Config for the default TLS settings, got %v %v", cfg, err)
+	}
+
+	cfg, err = TLS{InsecureSkipVerify: true, ServerName: "mcaas"}.Config()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cfg.InsecureSkipVerify || cfg.ServerName != "mcaas" {
+		t.Errorf("TLS settings not applied: %+v", cfg)
+	}
+
+	if _, err = (TLS{CAData: "not a certificate"}).Config(); err == nil {
+		t.Error("expected an error for ca_data that isn't PEM")
+	}
+}
+
+func TestLoadUnknownKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "profiles.yaml")
+	if err := os.WriteFile(path, []byte("profiles:\n  integ:\n    apiurl: https://mcaas.intg.hpedevops.net/mcaas\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Load(path); err == nil {
+		t.Error("expected an error for an unknown key")
+	}
+}
@@ -0,0 +1,340 @@
+// (C) Copyright 2023 Hewlett Packard Enterprise Development LP
+
+// Package recorder - an http.RoundTripper that records CaaS API exchanges to a fixture file and replays them
+// deterministically.  It is used to run the acceptance tests offline once a fixture has been recorded.
+package recorder
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"time"
+)
+
+const (
+	// ModeEnvVar - env-var used to select the recorder mode, one of ModeRecord or ModeReplay
+	ModeEnvVar = "HPEGL_CAAS_RECORDER_MODE"
+	// FixtureEnvVar - env-var holding the path of the fixture file to record to or replay from
+	FixtureEnvVar = "HPEGL_CAAS_RECORDER_FIXTURE"
+
+	// ModeRecord - pass requests to the API and capture the exchanges in the fixture file
+	ModeRecord = "record"
+	// ModeReplay - serve requests from the fixture file without contacting the API
+	ModeReplay = "replay"
+
+	// ReplayToken is the token handed to the client in replay mode, no IAM service is contacted
+	ReplayToken = "replayed-token"
+	// ReplayPollInterval is the interval used when polling for cluster state in replay mode
+	ReplayPollInterval = 10 * time.Millisecond
+
+	redacted = "REDACTED"
+)
+
+var (
+	// jwtRegexp matches anything that looks like a JWT, e.g. IAM tokens or service account tokens
+	jwtRegexp = regexp.MustCompile(`eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`)
+	// kubeconfigSecretRegexp matches the credential entries of a kubeconfig
+	kubeconfigSecretRegexp = regexp.MustCompile(`(?m)^(\s*(?:token|client-key-data|client-certificate-data|password):\s*).+$`)
+	// secretFields are JSON fields that are always scrubbed from recorded bodies
+	secretFields = []string{"clientSecret", "token"}
+
+	// recorders holds one Recorder per fixture path so that all clients created in a test
+	// share the same fixture and replay position
+	recorders   = make(map[string]*Recorder)
+	recordersMu sync.Mutex
+)
+
+// Interaction is a single recorded request/response exchange
+type Interaction struct {
+	Method       string `json:"method"`
+	URL          string `json:"url"`
+	RequestBody  string `json:"request_body,omitempty"`
+	StatusCode   int    `json:"status_code"`
+	Status       string `json:"status"`
+	ContentType  string `json:"content_type,omitempty"`
+	ResponseBody string `json:"response_body"`
+}
+
+// Fixture is the on-disk format of a recording
+type Fixture struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Recorder is an http.RoundTripper that either records or replays interactions
+type Recorder struct {
+	mode    string
+	path    string
+	base    http.RoundTripper
+	mu      sync.Mutex
+	fixture Fixture
+	used    []bool
+}
+
+// Assert that Recorder implements http.RoundTripper
+var _ http.RoundTripper = (*Recorder)(nil)
+
+// Mode returns the recorder mode set in the environment, "" if the recorder is not in use
+func Mode() string {
+	return os.Getenv(ModeEnvVar)
+}
+
+// Replaying returns true if interactions are being served from a fixture
+func Replaying() bool {
+	return Mode() == ModeReplay
+}
+
+// FromEnv returns the Recorder for the fixture set in the environment.  If no recorder
+// mode is set it returns nil.  base is the RoundTripper used to reach the API when recording.
+func FromEnv(base http.RoundTripper) (*Recorder, error) {
+	mode := Mode()
+	if mode == "" {
+		return nil, nil
+	}
+
+	if mode != ModeRecord && mode != ModeReplay {
+		return nil, fmt.Errorf("invalid %s %q, must be %q or %q", ModeEnvVar, mode, ModeRecord, ModeReplay)
+	}
+
+	path := os.Getenv(FixtureEnvVar)
+	if path == "" {
+		return nil, fmt.Errorf("%s must be set when %s is %q", FixtureEnvVar, ModeEnvVar, mode)
+	}
+
+	return New(mode, path, base)
+}
+
+// New returns the Recorder for path, creating it if this is the first use of the fixture
+func New(mode, path string, base http.RoundTripper) (*Recorder, error) {
+	recordersMu.Lock()
+	defer recordersMu.Unlock()
+
+	if r, ok := recorders[path]; ok && r.mode == mode {
+		return r, nil
+	}
+
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	r := &Recorder{
+		mode: mode,
+		path: path,
+		base: base,
+	}
+
+	if mode == ModeReplay {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("error reading fixture %s: %w", path, err)
+		}
+
+		if err = json.Unmarshal(data, &r.fixture); err != nil {
+			return nil, fmt.Errorf("error parsing fixture %s: %w", path, err)
+		}
+
+		r.used = make([]bool, len(r.fixture.Interactions))
+	}
+
+	recorders[path] = r
+
+	return r, nil
+}
+
+// Reset forgets the Recorder for path, the next New for path starts again from the beginning of the
+// fixture.  Tests call it when they finish so that a re-run with -count, or another test that uses the
+// same fixture, doesn't continue from where the last one stopped.
+func Reset(path string) {
+	recordersMu.Lock()
+	defer recordersMu.Unlock()
+
+	delete(recorders, path)
+}
+
+// RoundTrip implements http.RoundTripper
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	if r.mode == ModeReplay {
+		return r.replay(req)
+	}
+
+	return r.record(req)
+}
+
+func (r *Recorder) record(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil {
+		var err error
+		reqBody, err = io.ReadAll(req.Body)
+		if err != nil {
+			return nil, err
+		}
+		req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(reqBody))
+	}
+
+	resp, err := r.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	interaction := Interaction{
+		Method:       req.Method,
+		URL:          req.URL.RequestURI(),
+		RequestBody:  scrub(reqBody),
+		StatusCode:   resp.StatusCode,
+		Status:       resp.Status,
+		ContentType:  resp.Header.Get("Content-Type"),
+		ResponseBody: scrub(respBody),
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.fixture.Interactions = append(r.fixture.Interactions, interaction)
+
+	return resp, r.save()
+}
+
+// replay serves the first unused interaction that matches the method and URL of req.  Once all matching
+// interactions have been used the last one is served again, this keeps trailing refreshes deterministic.
+func (r *Recorder) replay(req *http.Request) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	url := req.URL.RequestURI()
+	match := -1
+	for i, interaction := range r.fixture.Interactions {
+		if interaction.Method != req.Method || interaction.URL != url {
+			continue
+		}
+
+		match = i
+		if !r.used[i] {
+			break
+		}
+	}
+
+	if match == -1 {
+		return nil, fmt.Errorf("no recorded interaction for %s %s in fixture %s", req.Method, url, r.path)
+	}
+	r.used[match] = true
+
+	interaction := r.fixture.Interactions[match]
+	header := make(http.Header)
+	if interaction.ContentType != "" {
+		header.Set("Content-Type", interaction.ContentType)
+	}
+
+	return &http.Response{
+		Status:        interaction.Status,
+		StatusCode:    interaction.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader([]byte(interaction.ResponseBody))),
+		ContentLength: int64(len(interaction.ResponseBody)),
+		Request:       req,
+	}, nil
+}
+
+// save writes the fixture to disk, it is called with r.mu held
+func (r *Recorder) save() error {
+	data, err := json.MarshalIndent(r.fixture, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(filepath.Dir(r.path), 0o750); err != nil {
+		return err
+	}
+
+	return os.WriteFile(r.path, data, 0o600)
+}
+
+// scrub removes tokens and kubeconfig credentials from a request or response body
+func scrub(body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+
+	var doc interface{}
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return jwtRegexp.ReplaceAllString(string(body), redacted)
+	}
+
+	doc = scrubValue(doc)
+
+	out, err := json.Marshal(doc)
+	if err != nil {
+		return redacted
+	}
+
+	return string(out)
+}
+
+func scrubValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for k, field := range val {
+			switch {
+			case k == "kubeconfig":
+				if s, ok := field.(string); ok {
+					val[k] = scrubKubeconfig(s)
+				}
+			case isSecretField(k):
+				val[k] = redacted
+			default:
+				val[k] = scrubValue(field)
+			}
+		}
+
+		return val
+	case []interface{}:
+		for i := range val {
+			val[i] = scrubValue(val[i])
+		}
+
+		return val
+	case string:
+		return jwtRegexp.ReplaceAllString(val, redacted)
+	default:
+		return val
+	}
+}
+
+// scrubKubeconfig redacts the credentials in a base64-encoded kubeconfig, the cluster entries are kept
+func scrubKubeconfig(kubeconfig string) string {
+	decoded, err := base64.StdEncoding.DecodeString(kubeconfig)
+	if err != nil {
+		return redacted
+	}
+
+	decoded = kubeconfigSecretRegexp.ReplaceAll(decoded, []byte("${1}"+redacted))
+	decoded = jwtRegexp.ReplaceAll(decoded, []byte(redacted))
+
+	return base64.StdEncoding.EncodeToString(decoded)
+}
+
+func isSecretField(name string) bool {
+	for _, f := range secretFields {
+		if f == name {
+			return true
+		}
+	}
+
+	return false
+}
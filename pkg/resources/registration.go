@@ -23,11 +23,17 @@ func (r Registration) Name() string {
 
 func (r Registration) SupportedDataSources() map[string]*schema.Resource {
 	return map[string]*schema.Resource{
-		"hpegl_caas_cluster_blueprint": resources.DataSourceClusterBlueprint(),
-		"hpegl_caas_site":              resources.DataSourceAppliance(),
-		"hpegl_caas_machine_blueprint": resources.DataSourceMachineBlueprint(),
-		"hpegl_caas_cluster":           resources.DataSourceCluster(),
-		"hpegl_caas_cluster_provider":  resources.DataSourceClusterProvider(),
+		"hpegl_caas_cluster_blueprint":          resources.DataSourceClusterBlueprint(),
+		"hpegl_caas_site":                       resources.DataSourceAppliance(),
+		"hpegl_caas_machine_blueprint":          resources.DataSourceMachineBlueprint(),
+		"hpegl_caas_cluster":                    resources.DataSourceCluster(),
+		"hpegl_caas_cluster_provider":           resources.DataSourceClusterProvider(),
+		"hpegl_caas_kubeconfig":                 resources.DataSourceKubeconfig(),
+		"hpegl_caas_cost_estimate":              resources.DataSourceCostEstimate(),
+		"hpegl_caas_cluster_provider_selection": resources.DataSourceClusterProviderSelection(),
+		"hpegl_caas_cluster_machines":           resources.DataSourceClusterMachines(),
+		"hpegl_caas_kubernetes_versions":        resources.DataSourceKubernetesVersions(),
+		"hpegl_caas_machine_size_catalog":       resources.DataSourceMachineSizeCatalog(),
 	}
 }
 
@@ -36,6 +42,8 @@ func (r Registration) SupportedResources() map[string]*schema.Resource {
 		"hpegl_caas_cluster_blueprint": resources.ClusterBlueprint(),
 		"hpegl_caas_cluster":           resources.Cluster(),
 		"hpegl_caas_machine_blueprint": resources.MachineBlueprint(),
+		"hpegl_caas_cluster_wait":      resources.ClusterWait(),
+		"hpegl_caas_kubeconfig_file":   resources.KubeconfigFile(),
 	}
 }
 
@@ -44,9 +52,30 @@ func (r Registration) ProviderSchemaEntry() *schema.Resource {
 		Schema: map[string]*schema.Schema{
 			constants.APIURL: {
 				Type:        schema.TypeString,
-				Required:    true,
-				DefaultFunc: schema.EnvDefaultFunc("HPEGL_CAAS_API_URL", "https://mcaas.us1.greenlake-hpe.com/mcaas"),
-				Description: "The URL to use for the CaaS API, can also be set with the HPEGL_CAAS_API_URL env var",
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc(constants.APIURLEnvVar, ""),
+				Description: "The URL to use for the CaaS API, can also be set with the HPEGL_CAAS_API_URL env var. " +
+					"Defaults to the api_url of the profile, or to " + constants.DefaultAPIURL,
+			},
+			constants.Profile: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc(constants.ProfileEnvVar, ""),
+				Description: "The name of a profile in the file named by the HPEGL_CAAS_PROFILES_FILE env var, " +
+					"it sets api_url, space_id, site_id and TLS settings unless they are set here. " +
+					"Can also be set with the HPEGL_CAAS_PROFILE env var",
+			},
+			constants.SpaceID: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc(constants.SpaceIDEnvVar, ""),
+				Description: "The default space_id for CaaS resources and data sources, can also be set with the HPEGL_CAAS_SPACE_ID env var",
+			},
+			constants.SiteID: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc(constants.SiteIDEnvVar, ""),
+				Description: "The default site_id for CaaS resources and data sources, can also be set with the HPEGL_CAAS_SITE_ID env var",
 			},
 		},
 	}
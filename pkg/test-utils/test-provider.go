@@ -15,6 +15,7 @@ import (
 	"github.com/hewlettpackard/hpegl-provider-lib/pkg/token/serviceclient"
 
 	"github.com/HewlettPackard/hpegl-containers-terraform-resources/pkg/client"
+	"github.com/HewlettPackard/hpegl-containers-terraform-resources/pkg/recorder"
 	"github.com/HewlettPackard/hpegl-containers-terraform-resources/pkg/resources"
 )
 
@@ -28,6 +29,17 @@ func providerConfigure(p *schema.Provider) schema.ConfigureContextFunc { // noli
 		if err != nil {
 			return nil, diag.Errorf("error in creating client: %s", err)
 		}
+
+		// When replaying recorded API exchanges no IAM service is contacted
+		if recorder.Replaying() {
+			return map[string]interface{}{
+				client.InitialiseClient{}.ServiceName(): cli,
+				common.TokenRetrieveFunctionKey: retrieve.TokenRetrieveFuncCtx(func(ctx context.Context) (string, error) {
+					return recorder.ReplayToken, nil
+				}),
+			}, nil
+		}
+
 		// Initialise token handler
 		h, err := serviceclient.NewHandler(d)
 		if err != nil {
@@ -10,12 +10,3 @@ func WorkerPresentInMachineSets(machineSets []mcaasapi.MachineSet, workername st
 	}
 	return false
 }
-
-func RemoveWorkerFromMachineSets(machineSets []mcaasapi.MachineSet, workername string) []mcaasapi.MachineSet {
-	for i, ms := range machineSets {
-		if ms.Name == workername {
-			return append(machineSets[:i], machineSets[i+1:]...)
-		}
-	}
-	return machineSets
-}
@@ -0,0 +1,387 @@
+// (C) Copyright 2023 Hewlett Packard Enterprise Development LP
+
+package utils
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Kubeconfig is the subset of a kubeconfig file used by the provider, fields that are
+// not listed are kept in the inline maps so that a kubeconfig can be written back unchanged
+type Kubeconfig struct {
+	APIVersion     string                 `yaml:"apiVersion,omitempty"`
+	Kind           string                 `yaml:"kind,omitempty"`
+	Clusters       []NamedCluster         `yaml:"clusters"`
+	Contexts       []NamedContext         `yaml:"contexts"`
+	Users          []NamedUser            `yaml:"users"`
+	CurrentContext string                 `yaml:"current-context,omitempty"`
+	Extra          map[string]interface{} `yaml:",inline"`
+}
+
+// NamedCluster is an entry in the clusters list of a kubeconfig
+type NamedCluster struct {
+	Name    string                 `yaml:"name"`
+	Cluster KubeconfigCluster      `yaml:"cluster"`
+	Extra   map[string]interface{} `yaml:",inline"`
+}
+
+// KubeconfigCluster holds the API server details for a cluster
+type KubeconfigCluster struct {
+	Server                   string                 `yaml:"server"`
+	CertificateAuthorityData string                 `yaml:"certificate-authority-data,omitempty"`
+	InsecureSkipTLSVerify    bool                   `yaml:"insecure-skip-tls-verify,omitempty"`
+	Extra                    map[string]interface{} `yaml:",inline"`
+}
+
+// NamedContext is an entry in the contexts list of a kubeconfig
+type NamedContext struct {
+	Name    string                 `yaml:"name"`
+	Context KubeconfigContext      `yaml:"context"`
+	Extra   map[string]interface{} `yaml:",inline"`
+}
+
+// KubeconfigContext ties a cluster to a user
+type KubeconfigContext struct {
+	Cluster    string                 `yaml:"cluster"`
+	User       string                 `yaml:"user"`
+	Namespace  string                 `yaml:"namespace,omitempty"`
+	Extensions []NamedExtension       `yaml:"extensions,omitempty"`
+	Extra      map[string]interface{} `yaml:",inline"`
+}
+
+// NamedExtension is an entry in the extensions list of a kubeconfig context
+type NamedExtension struct {
+	Name      string                 `yaml:"name"`
+	Extension map[string]interface{} `yaml:"extension"`
+}
+
+const (
+	// OwnerExtension is the name of the context extension that marks the entries written by the provider
+	OwnerExtension = "hpegl-caas"
+	ownerKey       = "cluster-id"
+)
+
+// NamedUser is an entry in the users list of a kubeconfig
+type NamedUser struct {
+	Name  string                 `yaml:"name"`
+	User  KubeconfigUser         `yaml:"user"`
+	Extra map[string]interface{} `yaml:",inline"`
+}
+
+// KubeconfigUser holds the credentials for a user
+type KubeconfigUser struct {
+	Token                 string                 `yaml:"token,omitempty"`
+	ClientCertificateData string                 `yaml:"client-certificate-data,omitempty"`
+	ClientKeyData         string                 `yaml:"client-key-data,omitempty"`
+	Exec                  *KubeconfigExec        `yaml:"exec,omitempty"`
+	Extra                 map[string]interface{} `yaml:",inline"`
+}
+
+// KubeconfigExec is the configuration of an exec credential plugin
+type KubeconfigExec struct {
+	APIVersion      string                 `yaml:"apiVersion"`
+	Command         string                 `yaml:"command"`
+	Args            []string               `yaml:"args,omitempty"`
+	InteractiveMode string                 `yaml:"interactiveMode,omitempty"`
+	Extra           map[string]interface{} `yaml:",inline"`
+}
+
+// DecodeKubeconfig decodes the base64-encoded kubeconfig returned by the CaaS API, it returns the
+// parsed kubeconfig along with the raw YAML
+func DecodeKubeconfig(kubeconfig string) (*Kubeconfig, string, error) {
+	raw, err := base64.StdEncoding.DecodeString(kubeconfig)
+	if err != nil {
+		return nil, "", fmt.Errorf("error decoding kubeconfig: %w", err)
+	}
+
+	kc, err := ParseKubeconfig(raw)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return kc, string(raw), nil
+}
+
+// ParseKubeconfig parses a kubeconfig YAML document
+func ParseKubeconfig(raw []byte) (*Kubeconfig, error) {
+	kc := new(Kubeconfig)
+	if err := yaml.Unmarshal(raw, kc); err != nil {
+		return nil, fmt.Errorf("error parsing kubeconfig: %w", err)
+	}
+
+	return kc, nil
+}
+
+// Marshal returns the kubeconfig as YAML
+func (k *Kubeconfig) Marshal() ([]byte, error) {
+	out, err := yaml.Marshal(k)
+	if err != nil {
+		return nil, fmt.Errorf("error writing kubeconfig: %w", err)
+	}
+
+	return out, nil
+}
+
+// CurrentCluster returns the cluster referenced by the current context, or the first
+// cluster if there is no current context
+func (k *Kubeconfig) CurrentCluster() (*KubeconfigCluster, error) {
+	name := ""
+	if ctx := k.currentContext(); ctx != nil {
+		name = ctx.Cluster
+	}
+
+	for i := range k.Clusters {
+		if name == "" || k.Clusters[i].Name == name {
+			return &k.Clusters[i].Cluster, nil
+		}
+	}
+
+	return nil, fmt.Errorf("cluster %q not found in kubeconfig", name)
+}
+
+// CurrentUser returns the user referenced by the current context, or the first user
+// if there is no current context
+func (k *Kubeconfig) CurrentUser() (*KubeconfigUser, error) {
+	name := ""
+	if ctx := k.currentContext(); ctx != nil {
+		name = ctx.User
+	}
+
+	for i := range k.Users {
+		if name == "" || k.Users[i].Name == name {
+			return &k.Users[i].User, nil
+		}
+	}
+
+	return nil, fmt.Errorf("user %q not found in kubeconfig", name)
+}
+
+func (k *Kubeconfig) currentContext() *KubeconfigContext {
+	for i := range k.Contexts {
+		if k.Contexts[i].Name == k.CurrentContext {
+			return &k.Contexts[i].Context
+		}
+	}
+
+	if len(k.Contexts) > 0 {
+		return &k.Contexts[0].Context
+	}
+
+	return nil
+}
+
+// TokenExpiry returns the expiry time held in the exp claim of a JWT, the signature is not verified
+func TokenExpiry(token string) (time.Time, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}, errors.New("token is not a JWT")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("error decoding token payload: %w", err)
+	}
+
+	var claims struct {
+		Exp *float64 `json:"exp"`
+	}
+	if err = json.Unmarshal(payload, &claims); err != nil {
+		return time.Time{}, fmt.Errorf("error parsing token payload: %w", err)
+	}
+
+	if claims.Exp == nil {
+		return time.Time{}, errors.New("token has no exp claim")
+	}
+
+	return time.Unix(int64(*claims.Exp), 0).UTC(), nil
+}
+
+// SetEntry adds the cluster, user and context called name, the context is marked as owned by owner.  Entries
+// called name are only replaced if they are owned by owner, otherwise an error is returned.
+func (k *Kubeconfig) SetEntry(name string, cluster KubeconfigCluster, user KubeconfigUser, namespace, owner string) error {
+	if current, found := k.EntryOwner(name); found && current != owner {
+		return fmt.Errorf("kubeconfig already has a cluster, user or context called %s that wasn't written for cluster %s", name, owner)
+	}
+	k.RemoveEntry(name)
+
+	k.Clusters = append(k.Clusters, NamedCluster{Name: name, Cluster: cluster})
+	k.Users = append(k.Users, NamedUser{Name: name, User: user})
+	k.Contexts = append(k.Contexts, NamedContext{
+		Name: name,
+		Context: KubeconfigContext{
+			Cluster:   name,
+			User:      name,
+			Namespace: namespace,
+			Extensions: []NamedExtension{
+				{Name: OwnerExtension, Extension: map[string]interface{}{ownerKey: owner}},
+			},
+		},
+	})
+
+	return nil
+}
+
+// EntryOwner returns the owner recorded by SetEntry for the entries called name.  found is true if there
+// is a cluster, user or context called name, owner is empty if they weren't written by SetEntry.
+func (k *Kubeconfig) EntryOwner(name string) (owner string, found bool) {
+	for _, c := range k.Clusters {
+		found = found || c.Name == name
+	}
+	for _, u := range k.Users {
+		found = found || u.Name == name
+	}
+
+	for _, c := range k.Contexts {
+		if c.Name != name {
+			continue
+		}
+		found = true
+
+		for _, ext := range c.Context.Extensions {
+			if ext.Name == OwnerExtension {
+				owner, _ = ext.Extension[ownerKey].(string)
+			}
+		}
+	}
+
+	return owner, found
+}
+
+// RemoveOwnedEntry removes the cluster, user and context called name if they are owned by owner, it
+// returns true if they were removed
+func (k *Kubeconfig) RemoveOwnedEntry(name, owner string) bool {
+	if current, found := k.EntryOwner(name); !found || current != owner {
+		return false
+	}
+	k.RemoveEntry(name)
+
+	return true
+}
+
+// RemoveEntry removes the cluster, user and context called name, if name is the current context
+// the current context is cleared
+func (k *Kubeconfig) RemoveEntry(name string) {
+	clusters := k.Clusters[:0]
+	for _, c := range k.Clusters {
+		if c.Name != name {
+			clusters = append(clusters, c)
+		}
+	}
+	k.Clusters = clusters
+
+	users := k.Users[:0]
+	for _, u := range k.Users {
+		if u.Name != name {
+			users = append(users, u)
+		}
+	}
+	k.Users = users
+
+	contexts := k.Contexts[:0]
+	for _, c := range k.Contexts {
+		if c.Name != name {
+			contexts = append(contexts, c)
+		}
+	}
+	k.Contexts = contexts
+
+	if k.CurrentContext == name {
+		k.CurrentContext = ""
+	}
+}
+
+// HasContext returns true if the kubeconfig has a context called name
+func (k *Kubeconfig) HasContext(name string) bool {
+	for _, c := range k.Contexts {
+		if c.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ReadKubeconfigFile reads the kubeconfig at path, if the file doesn't exist an empty kubeconfig is
+// returned and exists is false
+func ReadKubeconfigFile(path string) (kc *Kubeconfig, exists bool, err error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return &Kubeconfig{APIVersion: "v1", Kind: "Config"}, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("error reading kubeconfig %s: %w", path, err)
+	}
+
+	kc, err = ParseKubeconfig(data)
+	if err != nil {
+		return nil, true, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return kc, true, nil
+}
+
+// WriteKubeconfigFile writes kc to path with 0600 permissions.  The file is written to a temporary
+// file that is renamed over path, so that a partially-written kubeconfig is never seen.
+func WriteKubeconfigFile(path string, kc *Kubeconfig) error {
+	data, err := kc.Marshal()
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(path)
+	if err = os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("error creating directory for kubeconfig %s: %w", path, err)
+	}
+
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".tmp")
+	if err != nil {
+		return fmt.Errorf("error writing kubeconfig %s: %w", path, err)
+	}
+	defer os.Remove(tmp.Name())
+
+	// CreateTemp creates the file with 0600, but set it explicitly since we rely on it
+	if err = tmp.Chmod(0o600); err != nil {
+		tmp.Close()
+
+		return fmt.Errorf("error writing kubeconfig %s: %w", path, err)
+	}
+
+	if _, err = tmp.Write(data); err != nil {
+		tmp.Close()
+
+		return fmt.Errorf("error writing kubeconfig %s: %w", path, err)
+	}
+
+	if err = tmp.Close(); err != nil {
+		return fmt.Errorf("error writing kubeconfig %s: %w", path, err)
+	}
+
+	if err = os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("error writing kubeconfig %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// ExpandHome replaces a leading ~ in path with the user's home directory
+func ExpandHome(path string) (string, error) {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(home, strings.TrimPrefix(path, "~")), nil
+}
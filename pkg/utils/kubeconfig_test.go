@@ -0,0 +1,167 @@
+// (C) Copyright 2023 Hewlett Packard Enterprise Development LP
+
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// userKubeconfig is a kubeconfig that the user already had before any CaaS contexts were merged into it
+const userKubeconfig = `apiVersion: v1
+kind: Config
+preferences:
+  colors: true
+clusters:
+- name: prod
+  cluster:
+    server: https://prod.example.com:6443
+contexts:
+- name: prod
+  context:
+    cluster: prod
+    user: prod
+users:
+- name: prod
+  user:
+    token: prod-token
+current-context: prod
+`
+
+const (
+	testClusterID  = "5d1b7f5e-2b0e-4c6a-9d5f-8f0f3c8e1a27"
+	otherClusterID = "3f31daa9-9777-4c06-a4d0-e49215f5e48c"
+)
+
+func writeUserKubeconfig(t *testing.T) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(path, []byte(userKubeconfig), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func readKubeconfig(t *testing.T, path string) *Kubeconfig {
+	t.Helper()
+
+	kc, exists, err := ReadKubeconfigFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !exists {
+		t.Fatalf("%s doesn't exist", path)
+	}
+
+	return kc
+}
+
+func setTestEntry(kc *Kubeconfig, name, owner, server string) error {
+	return kc.SetEntry(name, KubeconfigCluster{Server: server}, KubeconfigUser{Token: "caas-token"}, "default", owner)
+}
+
+func TestKubeconfigSetEntry(t *testing.T) {
+	path := writeUserKubeconfig(t)
+
+	kc := readKubeconfig(t, path)
+	if err := setTestEntry(kc, "caas", testClusterID, "https://caas.example.com:6443"); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteKubeconfigFile(path, kc); err != nil {
+		t.Fatal(err)
+	}
+
+	kc = readKubeconfig(t, path)
+	if len(kc.Contexts) != 2 || len(kc.Clusters) != 2 || len(kc.Users) != 2 {
+		t.Fatalf("expected 2 contexts, clusters and users, got %d %d %d", len(kc.Contexts), len(kc.Clusters), len(kc.Users))
+	}
+	if kc.CurrentContext != "prod" {
+		t.Errorf("current context changed to %s", kc.CurrentContext)
+	}
+	if _, ok := kc.Extra["preferences"]; !ok {
+		t.Error("preferences were not kept")
+	}
+	if owner, found := kc.EntryOwner("caas"); !found || owner != testClusterID {
+		t.Errorf("caas is owned by %q, expected %s", owner, testClusterID)
+	}
+	if owner, found := kc.EntryOwner("prod"); !found || owner != "" {
+		t.Errorf("prod is owned by %q, expected no owner", owner)
+	}
+
+	// Writing the same cluster again replaces its entries
+	if err := setTestEntry(kc, "caas", testClusterID, "https://caas2.example.com:6443"); err != nil {
+		t.Fatal(err)
+	}
+	if len(kc.Contexts) != 2 {
+		t.Errorf("expected 2 contexts after writing caas again, got %d", len(kc.Contexts))
+	}
+	if cluster := kc.Clusters[len(kc.Clusters)-1]; cluster.Name != "caas" || cluster.Cluster.Server != "https://caas2.example.com:6443" {
+		t.Errorf("caas cluster not replaced: %+v", cluster)
+	}
+}
+
+func TestKubeconfigSetEntryConflict(t *testing.T) {
+	path := writeUserKubeconfig(t)
+	kc := readKubeconfig(t, path)
+
+	// The user's own context
+	if err := setTestEntry(kc, "prod", testClusterID, "https://caas.example.com:6443"); err == nil {
+		t.Error("expected an error overwriting a context that isn't owned")
+	}
+
+	// A context written for another cluster
+	if err := setTestEntry(kc, "caas", otherClusterID, "https://caas.example.com:6443"); err != nil {
+		t.Fatal(err)
+	}
+	if err := setTestEntry(kc, "caas", testClusterID, "https://caas.example.com:6443"); err == nil {
+		t.Error("expected an error overwriting a context owned by another cluster")
+	}
+
+	// Only a cluster entry with the name
+	kc.Clusters = append(kc.Clusters, NamedCluster{Name: "staging"})
+	if err := setTestEntry(kc, "staging", testClusterID, "https://caas.example.com:6443"); err == nil {
+		t.Error("expected an error overwriting a cluster entry that isn't owned")
+	}
+
+	if server := kc.Clusters[0].Cluster.Server; server != "https://prod.example.com:6443" {
+		t.Errorf("prod cluster changed to %s", server)
+	}
+}
+
+func TestKubeconfigRemoveOwnedEntry(t *testing.T) {
+	path := writeUserKubeconfig(t)
+
+	kc := readKubeconfig(t, path)
+	if err := setTestEntry(kc, "caas", testClusterID, "https://caas.example.com:6443"); err != nil {
+		t.Fatal(err)
+	}
+	kc.CurrentContext = "caas"
+	if err := WriteKubeconfigFile(path, kc); err != nil {
+		t.Fatal(err)
+	}
+
+	kc = readKubeconfig(t, path)
+	if kc.RemoveOwnedEntry("prod", testClusterID) {
+		t.Error("removed the user's prod context")
+	}
+	if kc.RemoveOwnedEntry("caas", otherClusterID) {
+		t.Error("removed a context owned by another cluster")
+	}
+	if !kc.RemoveOwnedEntry("caas", testClusterID) {
+		t.Error("caas context not removed")
+	}
+	if err := WriteKubeconfigFile(path, kc); err != nil {
+		t.Fatal(err)
+	}
+
+	kc = readKubeconfig(t, path)
+	if len(kc.Contexts) != 1 || kc.Contexts[0].Name != "prod" || len(kc.Clusters) != 1 || len(kc.Users) != 1 {
+		t.Errorf("expected only the prod entries to be left: %+v", kc)
+	}
+	if kc.CurrentContext != "" {
+		t.Errorf("current context is %s, expected it to be cleared", kc.CurrentContext)
+	}
+}
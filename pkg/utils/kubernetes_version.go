@@ -0,0 +1,169 @@
+// (C) Copyright 2023 Hewlett Packard Enterprise Development LP
+
+package utils
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// hpeSuffix is the prefix of the HPE build suffix of a kubernetes version, e.g. 1.23.13-hpe2
+const hpeSuffix = "hpe"
+
+// KubernetesVersion is a kubernetes version such as 1.23.13-hpe2
+type KubernetesVersion struct {
+	Major int
+	Minor int
+	Patch int
+	// HPE is the number of the HPE build suffix, 0 if there is none
+	HPE int
+	// Suffix is any other suffix, such as a prerelease suffix rc.1
+	Suffix string
+	// Original is the version as it was parsed
+	Original string
+}
+
+// ParseKubernetesVersion parses a kubernetes version, with or without a leading v and an HPE build suffix
+func ParseKubernetesVersion(version string) (KubernetesVersion, error) {
+	v := KubernetesVersion{Original: version}
+
+	core := strings.TrimPrefix(strings.TrimSpace(version), "v")
+	if i := strings.Index(core, "-"); i >= 0 {
+		core, v.Suffix = core[:i], core[i+1:]
+	}
+
+	parts := strings.Split(core, ".")
+	if len(parts) < 2 || len(parts) > 3 {
+		return v, fmt.Errorf("invalid kubernetes version %q", version)
+	}
+
+	numbers := []*int{&v.Major, &v.Minor, &v.Patch}
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return v, fmt.Errorf("invalid kubernetes version %q", version)
+		}
+		*numbers[i] = n
+	}
+
+	if strings.HasPrefix(v.Suffix, hpeSuffix) {
+		if n, err := strconv.Atoi(strings.TrimPrefix(v.Suffix, hpeSuffix)); err == nil {
+			v.HPE, v.Suffix = n, ""
+		}
+	}
+
+	return v, nil
+}
+
+// MinorVersion returns the major and minor version, e.g. 1.23
+func (v KubernetesVersion) MinorVersion() string {
+	return fmt.Sprintf("%d.%d", v.Major, v.Minor)
+}
+
+// Compare returns -1, 0 or 1 if v is older than, the same as or newer than o.  Versions are compared
+// by major, minor and patch, then by the HPE build number, and then by any other suffix as semver
+// prereleases are, so a version without a suffix is newer than one with a suffix.
+func (v KubernetesVersion) Compare(o KubernetesVersion) int {
+	for _, c := range [][2]int{{v.Major, o.Major}, {v.Minor, o.Minor}, {v.Patch, o.Patch}, {v.HPE, o.HPE}} {
+		if c[0] != c[1] {
+			if c[0] < c[1] {
+				return -1
+			}
+
+			return 1
+		}
+	}
+
+	return comparePrerelease(v.Suffix, o.Suffix)
+}
+
+// comparePrerelease compares two prerelease suffixes the way semver does, no suffix is newer than any
+// suffix, and the dot separated identifiers are compared numerically when both are numbers and as
+// strings otherwise, with numbers older than strings
+func comparePrerelease(a, b string) int {
+	switch {
+	case a == b:
+		return 0
+	case a == "":
+		return 1
+	case b == "":
+		return -1
+	}
+
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		an, aErr := strconv.Atoi(as[i])
+		bn, bErr := strconv.Atoi(bs[i])
+
+		switch {
+		case aErr == nil && bErr == nil:
+			if an != bn {
+				if an < bn {
+					return -1
+				}
+
+				return 1
+			}
+		case aErr == nil:
+			return -1
+		case bErr == nil:
+			return 1
+		default:
+			if c := strings.Compare(as[i], bs[i]); c != 0 {
+				return c
+			}
+		}
+	}
+
+	switch {
+	case len(as) < len(bs):
+		return -1
+	case len(as) > len(bs):
+		return 1
+	}
+
+	return 0
+}
+
+// SortKubernetesVersions sorts versions from oldest to newest
+func SortKubernetesVersions(versions []KubernetesVersion) {
+	sort.SliceStable(versions, func(i, j int) bool {
+		return versions[i].Compare(versions[j]) < 0
+	})
+}
+
+// KubernetesUpgradePath returns the versions to upgrade through to get from from to to, kubernetes
+// can only be upgraded one minor version at a time so this is the newest patch of each minor version
+// in between, followed by to.  versions must be sorted.
+func KubernetesUpgradePath(versions []KubernetesVersion, from, to KubernetesVersion) []KubernetesVersion {
+	var path []KubernetesVersion
+
+	current := from
+	for current.Compare(to) < 0 {
+		var next *KubernetesVersion
+		for i := range versions {
+			candidate := versions[i]
+			if candidate.Compare(current) <= 0 || candidate.Compare(to) > 0 {
+				continue
+			}
+
+			// Only the current minor version or the next one can be upgraded to
+			if candidate.Major != current.Major || candidate.Minor > current.Minor+1 {
+				continue
+			}
+
+			next = &versions[i]
+		}
+
+		if next == nil {
+			return nil
+		}
+
+		path = append(path, *next)
+		current = *next
+	}
+
+	return path
+}
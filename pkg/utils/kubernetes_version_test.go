@@ -0,0 +1,94 @@
+// (C) Copyright 2023 Hewlett Packard Enterprise Development LP
+
+package utils
+
+import (
+	"reflect"
+	"testing"
+)
+
+func parseTestVersions(t *testing.T, versions ...string) []KubernetesVersion {
+	t.Helper()
+
+	parsed := make([]KubernetesVersion, 0, len(versions))
+	for _, version := range versions {
+		v, err := ParseKubernetesVersion(version)
+		if err != nil {
+			t.Fatalf("error parsing %s: %s", version, err)
+		}
+		parsed = append(parsed, v)
+	}
+
+	return parsed
+}
+
+func originals(versions []KubernetesVersion) []string {
+	out := make([]string, 0, len(versions))
+	for _, v := range versions {
+		out = append(out, v.Original)
+	}
+
+	return out
+}
+
+func TestSortKubernetesVersions(t *testing.T) {
+	versions := parseTestVersions(t, "1.23.13-hpe2", "v1.24.1-hpe1", "1.23.9-hpe1", "1.23.13-hpe10", "1.23.13", "1.22.17-hpe1")
+	SortKubernetesVersions(versions)
+
+	want := []string{"1.22.17-hpe1", "1.23.9-hpe1", "1.23.13", "1.23.13-hpe2", "1.23.13-hpe10", "v1.24.1-hpe1"}
+	if got := originals(versions); !reflect.DeepEqual(got, want) {
+		t.Errorf("sorted versions are %v, want %v", got, want)
+	}
+}
+
+func TestCompareKubernetesVersions(t *testing.T) {
+	testCases := []struct {
+		older, newer string
+	}{
+		{older: "1.24.0-rc.1", newer: "1.24.0"},
+		{older: "1.24.0-alpha", newer: "1.24.0-beta"},
+		{older: "1.24.0-rc.2", newer: "1.24.0-rc.10"},
+		{older: "1.24.0-rc.1", newer: "1.24.0-rc.1.1"},
+		{older: "1.24.0-1", newer: "1.24.0-rc"},
+		{older: "1.24.0", newer: "1.24.0-hpe1"},
+		{older: "1.23.13-hpe10", newer: "1.24.0-rc.1"},
+	}
+
+	for _, tc := range testCases {
+		versions := parseTestVersions(t, tc.older, tc.newer)
+		if c := versions[0].Compare(versions[1]); c != -1 {
+			t.Errorf("comparing %s with %s returned %d, want -1", tc.older, tc.newer, c)
+		}
+		if c := versions[1].Compare(versions[0]); c != 1 {
+			t.Errorf("comparing %s with %s returned %d, want 1", tc.newer, tc.older, c)
+		}
+	}
+
+	versions := parseTestVersions(t, "v1.24.0-rc.1", "1.24.0-rc.1")
+	if c := versions[0].Compare(versions[1]); c != 0 {
+		t.Errorf("comparing %s with %s returned %d, want 0", versions[0].Original, versions[1].Original, c)
+	}
+}
+
+func TestParseKubernetesVersionInvalid(t *testing.T) {
+	for _, version := range []string{"", "1", "1.x.3", "1.2.3.4", "latest"} {
+		if _, err := ParseKubernetesVersion(version); err == nil {
+			t.Errorf("expected an error parsing %q", version)
+		}
+	}
+}
+
+func TestKubernetesUpgradePath(t *testing.T) {
+	versions := parseTestVersions(t, "1.22.17-hpe1", "1.23.9-hpe1", "1.23.13-hpe2", "1.24.1-hpe1", "1.24.8-hpe1", "1.25.4-hpe1")
+	from := parseTestVersions(t, "1.22.17-hpe1")[0]
+	to := versions[len(versions)-1]
+
+	want := []string{"1.23.13-hpe2", "1.24.8-hpe1", "1.25.4-hpe1"}
+	if got := originals(KubernetesUpgradePath(versions, from, to)); !reflect.DeepEqual(got, want) {
+		t.Errorf("upgrade path is %v, want %v", got, want)
+	}
+
+	if path := KubernetesUpgradePath(versions, to, to); len(path) != 0 {
+		t.Errorf("expected no upgrade path to the same version, got %v", originals(path))
+	}
+}
@@ -0,0 +1,135 @@
+// (C) Copyright 2023 Hewlett Packard Enterprise Development LP
+
+package utils
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	// RIPEMD160 is the hash assumed by openpgp for keys that don't list their preferred hashes
+	_ "golang.org/x/crypto/ripemd160"
+)
+
+const (
+	keybasePrefix    = "keybase:"
+	keybaseTimeout   = 30 * time.Second
+	armoredKeyHeader = "-----BEGIN PGP PUBLIC KEY BLOCK-----"
+)
+
+// keybaseLookupURL is the keybase user lookup endpoint, a variable so that it can be pointed at a test server
+var keybaseLookupURL = "https://keybase.io/_/api/1.0/user/lookup.json"
+
+// RetrievePGPKey returns the public key for pgpKey, which is either keybase:<username>, an armored
+// public key or a base64-encoded binary public key.  Only the public key is fetched from keybase,
+// all encryption is done locally.
+func RetrievePGPKey(ctx context.Context, pgpKey string) (string, error) {
+	if !strings.HasPrefix(pgpKey, keybasePrefix) {
+		return pgpKey, nil
+	}
+
+	username := strings.TrimPrefix(pgpKey, keybasePrefix)
+	if username == "" {
+		return "", errors.New("no keybase username given in pgp_key")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, keybaseTimeout)
+	defer cancel()
+
+	query := url.Values{"usernames": {username}, "fields": {"public_keys"}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, keybaseLookupURL+"?"+query.Encode(), nil)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("error fetching key for keybase user %s: %w", username, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("error fetching key for keybase user %s: %s", username, resp.Status)
+	}
+
+	var lookup struct {
+		Them []struct {
+			PublicKeys struct {
+				Primary struct {
+					Bundle string `json:"bundle"`
+				} `json:"primary"`
+			} `json:"public_keys"`
+		} `json:"them"`
+	}
+	if err = json.NewDecoder(resp.Body).Decode(&lookup); err != nil {
+		return "", fmt.Errorf("error parsing keybase response for user %s: %w", username, err)
+	}
+
+	if len(lookup.Them) == 0 || lookup.Them[0].PublicKeys.Primary.Bundle == "" {
+		return "", fmt.Errorf("no public key found for keybase user %s", username)
+	}
+
+	return lookup.Them[0].PublicKeys.Primary.Bundle, nil
+}
+
+// EncryptWithPGPKey encrypts plaintext to publicKey, which is an armored or base64-encoded binary public
+// key.  It returns the base64-encoded encrypted message and the fingerprint of the key used.
+func EncryptWithPGPKey(plaintext []byte, publicKey string) (string, string, error) {
+	entity, err := parsePGPKey(publicKey)
+	if err != nil {
+		return "", "", err
+	}
+
+	buf := new(bytes.Buffer)
+	w, err := openpgp.Encrypt(buf, []*openpgp.Entity{entity}, nil, nil, nil)
+	if err != nil {
+		return "", "", fmt.Errorf("error encrypting with pgp key: %w", err)
+	}
+
+	if _, err = w.Write(plaintext); err != nil {
+		return "", "", fmt.Errorf("error encrypting with pgp key: %w", err)
+	}
+
+	if err = w.Close(); err != nil {
+		return "", "", fmt.Errorf("error encrypting with pgp key: %w", err)
+	}
+
+	fingerprint := hex.EncodeToString(entity.PrimaryKey.Fingerprint[:])
+
+	return base64.StdEncoding.EncodeToString(buf.Bytes()), fingerprint, nil
+}
+
+func parsePGPKey(publicKey string) (*openpgp.Entity, error) {
+	var entities openpgp.EntityList
+	var err error
+
+	if strings.Contains(publicKey, armoredKeyHeader) {
+		entities, err = openpgp.ReadArmoredKeyRing(strings.NewReader(publicKey))
+	} else {
+		var binaryKey []byte
+		binaryKey, err = base64.StdEncoding.DecodeString(strings.TrimSpace(publicKey))
+		if err != nil {
+			return nil, fmt.Errorf("pgp_key is neither an armored nor a base64-encoded public key: %w", err)
+		}
+		entities, err = openpgp.ReadKeyRing(bytes.NewReader(binaryKey))
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("error reading pgp key: %w", err)
+	}
+
+	if len(entities) == 0 {
+		return nil, errors.New("no keys found in pgp_key")
+	}
+
+	return entities[0], nil
+}
@@ -0,0 +1,182 @@
+// (C) Copyright 2023 Hewlett Packard Enterprise Development LP
+
+package utils
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+)
+
+// testPGPEntity returns a new key pair with its armored and base64-encoded binary public key
+func testPGPEntity(t *testing.T) (entity *openpgp.Entity, armored, binary string) {
+	t.Helper()
+
+	entity, err := openpgp.NewEntity("test", "", "test@example.com", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf := new(bytes.Buffer)
+	if err = entity.Serialize(buf); err != nil {
+		t.Fatal(err)
+	}
+	binary = base64.StdEncoding.EncodeToString(buf.Bytes())
+
+	armoredBuf := new(bytes.Buffer)
+	w, err := armor.Encode(armoredBuf, openpgp.PublicKeyType, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = w.Write(buf.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+	if err = w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return entity, armoredBuf.String(), binary
+}
+
+func TestParsePGPKey(t *testing.T) {
+	entity, armored, binary := testPGPEntity(t)
+
+	testCases := []struct {
+		name      string
+		key       string
+		errorText string
+	}{
+		{name: "armored", key: armored},
+		{name: "base64", key: binary},
+		{name: "base64 with whitespace", key: "\n" + binary + "\n"},
+		{name: "not a key", key: "not a key", errorText: "neither an armored nor a base64-encoded"},
+		{name: "base64 of something else", key: base64.StdEncoding.EncodeToString([]byte("abc")), errorText: "error reading pgp key"},
+		{name: "empty armored block", key: armoredKeyHeader + "\n\n-----END PGP PUBLIC KEY BLOCK-----", errorText: "no keys found"},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			parsed, err := parsePGPKey(tc.key)
+			if tc.errorText != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.errorText) {
+					t.Fatalf("expected error containing %q, got %v", tc.errorText, err)
+				}
+
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !bytes.Equal(parsed.PrimaryKey.Fingerprint, entity.PrimaryKey.Fingerprint) {
+				t.Error("parsed a different key")
+			}
+		})
+	}
+}
+
+func TestRetrievePGPKey(t *testing.T) {
+	_, armored, binary := testPGPEntity(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Query().Get("usernames") != "someone" {
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"them": []interface{}{}})
+
+			return
+		}
+
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"them": []interface{}{map[string]interface{}{
+				"public_keys": map[string]interface{}{"primary": map[string]interface{}{"bundle": armored}},
+			}},
+		})
+	}))
+	defer server.Close()
+
+	lookupURL := keybaseLookupURL
+	keybaseLookupURL = server.URL
+	defer func() { keybaseLookupURL = lookupURL }()
+
+	testCases := []struct {
+		name      string
+		pgpKey    string
+		expected  string
+		errorText string
+	}{
+		{name: "armored", pgpKey: armored, expected: armored},
+		{name: "base64", pgpKey: binary, expected: binary},
+		{name: "keybase", pgpKey: "keybase:someone", expected: armored},
+		{name: "unknown keybase user", pgpKey: "keybase:nobody", errorText: "no public key found for keybase user nobody"},
+		{name: "no keybase user", pgpKey: "keybase:", errorText: "no keybase username"},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			key, err := RetrievePGPKey(context.Background(), tc.pgpKey)
+			if tc.errorText != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.errorText) {
+					t.Fatalf("expected error containing %q, got %v", tc.errorText, err)
+				}
+
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if key != tc.expected {
+				t.Errorf("got key %q, expected %q", key, tc.expected)
+			}
+		})
+	}
+}
+
+func TestEncryptWithPGPKey(t *testing.T) {
+	entity, armored, binary := testPGPEntity(t)
+	plaintext := []byte("apiVersion: v1\nkind: Config\n")
+
+	for name, key := range map[string]string{"armored": armored, "base64": binary} {
+		key := key
+		t.Run(name, func(t *testing.T) {
+			encrypted, fingerprint, err := EncryptWithPGPKey(plaintext, key)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if fingerprint != hex.EncodeToString(entity.PrimaryKey.Fingerprint[:]) {
+				t.Errorf("got fingerprint %s", fingerprint)
+			}
+
+			ciphertext, err := base64.StdEncoding.DecodeString(encrypted)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			md, err := openpgp.ReadMessage(bytes.NewReader(ciphertext), openpgp.EntityList{entity}, nil, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			decrypted, err := io.ReadAll(md.UnverifiedBody)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !bytes.Equal(decrypted, plaintext) {
+				t.Errorf("decrypted %q, expected %q", decrypted, plaintext)
+			}
+		})
+	}
+}
@@ -1,6 +1,10 @@
 package utils
 
-import "github.com/HewlettPackard/hpegl-containers-go-sdk/pkg/mcaasapi"
+import (
+	"net/http"
+
+	"github.com/HewlettPackard/hpegl-containers-go-sdk/pkg/mcaasapi"
+)
 
 func GetErrorMessage(err error, statusCode int) string {
 	swaggerErr, ok := err.(mcaasapi.GenericSwaggerError)
@@ -38,3 +42,18 @@ func GetErrorMessage(err error, statusCode int) string {
 		return ""
 	}
 }
+
+// IsRetryableStatus returns true if a request that failed with statusCode may succeed if it is retried, a
+// statusCode of zero means that no response was received
+func IsRetryableStatus(statusCode int) bool {
+	switch {
+	case statusCode == 0:
+		return true
+	case statusCode == http.StatusRequestTimeout || statusCode == http.StatusTooManyRequests:
+		return true
+	case statusCode >= http.StatusInternalServerError:
+		return true
+	default:
+		return false
+	}
+}
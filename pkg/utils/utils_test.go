@@ -0,0 +1,31 @@
+// (C) Copyright 2023 Hewlett Packard Enterprise Development LP
+
+package utils
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestIsRetryableStatus(t *testing.T) {
+	testCases := []struct {
+		statusCode int
+		retryable  bool
+	}{
+		{statusCode: 0, retryable: true},
+		{statusCode: http.StatusBadRequest, retryable: false},
+		{statusCode: http.StatusUnauthorized, retryable: false},
+		{statusCode: http.StatusForbidden, retryable: false},
+		{statusCode: http.StatusNotFound, retryable: false},
+		{statusCode: http.StatusRequestTimeout, retryable: true},
+		{statusCode: http.StatusTooManyRequests, retryable: true},
+		{statusCode: http.StatusInternalServerError, retryable: true},
+		{statusCode: http.StatusServiceUnavailable, retryable: true},
+	}
+
+	for _, tc := range testCases {
+		if got := IsRetryableStatus(tc.statusCode); got != tc.retryable {
+			t.Errorf("IsRetryableStatus(%d) is %t, expected %t", tc.statusCode, got, tc.retryable)
+		}
+	}
+}